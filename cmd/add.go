@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"remotelink/config"
 	"remotelink/models"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
@@ -21,8 +23,17 @@ var addHuhCmd = &cobra.Command{
 			username    string
 			keyPath     string
 			defaultPath string
+			jump        string
+			group       string
+			tagsStr     string
 		)
 
+		// 점프 호스트 후보: 기존 서버 목록
+		jumpOptions := []huh.Option[string]{huh.NewOption("(none)", "")}
+		for _, server := range config.Servers {
+			jumpOptions = append(jumpOptions, huh.NewOption(server.ServerName, server.ServerName))
+		}
+
 		form := huh.NewForm(
 			huh.NewGroup(
 				huh.NewInput().
@@ -55,6 +66,26 @@ var addHuhCmd = &cobra.Command{
 					Title("Default Path").
 					Value(&defaultPath).
 					Placeholder("/home"),
+
+				huh.NewSelect[string]().
+					Title("Jump Host").
+					Description("Reach this server through another configured server").
+					Options(jumpOptions...).
+					Value(&jump),
+			),
+			huh.NewGroup(
+				huh.NewInput().
+					Title("Group").
+					Description("Servers are grouped by this in pickers and 'ls'").
+					Value(&group).
+					Suggestions(config.Groups()).
+					Placeholder("web"),
+
+				huh.NewInput().
+					Title("Tags").
+					Description("Comma-separated, used by --tag").
+					Value(&tagsStr).
+					Placeholder("prod, nginx"),
 			),
 		)
 
@@ -72,6 +103,15 @@ var addHuhCmd = &cobra.Command{
 			}
 		}
 
+		// 태그 정리: 공백 제거, 빈 값과 중복 제외
+		var tags []string
+		for _, tag := range strings.Split(tagsStr, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag != "" && !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+
 		// Save server
 		newServer := models.Server{
 			ServerName:  serverName,
@@ -80,14 +120,16 @@ var addHuhCmd = &cobra.Command{
 			Username:    username,
 			KeyPath:     keyPath,
 			DefaultPath: defaultPath,
+			Jump:        jump,
+			Group:       strings.TrimSpace(group),
+			Tags:        tags,
 			Containers:  []models.Container{},
 		}
 
 		config.Servers = append(config.Servers, newServer)
-		config.ServerConfig.Set("servers", config.Servers)
 
-		if err := config.ServerConfig.WriteConfig(); err != nil {
-			return fmt.Errorf("failed to save: %w", err)
+		if err := config.SaveServers(); err != nil {
+			return err
 		}
 
 		fmt.Printf("Server '%s' added successfully!\n", serverName)
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"remotelink/config"
+	"remotelink/models"
+	remotessh "remotelink/ssh"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	composeDir   string
+	composeFiles []string
+)
+
+var composeCmd = &cobra.Command{
+	Use:   "compose [server] [project] [ps|up|down|pull|logs] [args...]",
+	Short: "Run compose commands for a project on a server",
+	Long: `Run compose commands for a compose project on a server.
+
+The project's directory and compose files are read from the labels compose puts on its
+containers, so there is no need to know where the stack lives. They are remembered, so a
+project taken down can be brought back up. For a project never seen, give --dir and, if
+needed, --file before the server. Without a project the server's compose projects are
+listed; without an action, ps is run. up runs detached. Any further arguments are passed
+to compose.
+
+  remotelink compose prod
+  remotelink compose prod shop pull
+  remotelink compose prod shop up --build
+  remotelink compose prod shop logs -f web
+  remotelink compose --dir /srv/blog prod blog up`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(config.Servers) == 0 {
+			fmt.Println("❌ No servers configured")
+			return nil
+		}
+
+		server, err := containerServer(args)
+		if err != nil {
+			return err
+		}
+
+		if len(args) < 2 {
+			projects, err := fetchComposeProjects(server)
+			if err != nil {
+				return err
+			}
+			printComposeProjects(server, projects)
+			return nil
+		}
+
+		action := remotessh.ComposePs
+		var extra []string
+		if len(args) > 2 {
+			action, extra = args[2], args[3:]
+		}
+		if err := remotessh.ValidateComposeAction(action); err != nil {
+			return err
+		}
+
+		projects, err := fetchComposeProjects(server)
+		if err != nil {
+			return err
+		}
+		project := remotessh.ComposeProject{Name: args[1]}
+		if i := slices.IndexFunc(projects, func(p remotessh.ComposeProject) bool { return p.Name == args[1] }); i >= 0 {
+			project = projects[i]
+		} else if composeDir == "" {
+			return fmt.Errorf("compose project '%s' not found on %s (use --dir to give its directory)", args[1], server.ServerName)
+		}
+		if composeDir != "" {
+			project.WorkingDir, project.ConfigFiles = composeDir, composeFiles
+			config.RecordComposeProjects(server.ServerName, map[string]config.ComposeLocation{
+				project.Name: {WorkingDir: project.WorkingDir, ConfigFiles: project.ConfigFiles},
+			})
+		}
+
+		command, err := remotessh.ComposeCommand(server, project, action, extra)
+		if err != nil {
+			return err
+		}
+
+		config.RecordServerUse(server.ServerName)
+		fmt.Printf("\n🐙 compose %s for %s on %s (%s)\n\n", action, project.Name, server.ServerName, project.WorkingDir)
+
+		if err := remotessh.Interactive(server, command); err != nil {
+			return fmt.Errorf("❌ compose %s failed: %w", action, err)
+		}
+		return nil
+	},
+}
+
+// fetchComposeProjects lists the compose projects on the server with a spinner, together with
+// the remembered ones that no longer have containers.
+func fetchComposeProjects(server models.Server) ([]remotessh.ComposeProject, error) {
+	var projects []remotessh.ComposeProject
+	var fetchErr error
+	err := spinner.New().
+		Title(fmt.Sprintf("Fetching compose projects from %s...", server.ServerName)).
+		Action(func() {
+			projects, fetchErr = remotessh.ComposeProjects(server)
+		}).
+		Run()
+	if err != nil {
+		return nil, err
+	}
+	if fetchErr != nil {
+		return nil, fmt.Errorf("❌ %w", fetchErr)
+	}
+	return withRememberedProjects(server, projects), nil
+}
+
+// withRememberedProjects records where the projects live and adds the remembered projects
+// missing from them, such as ones taken down, sorted by name.
+func withRememberedProjects(server models.Server, projects []remotessh.ComposeProject) []remotessh.ComposeProject {
+	locations := map[string]config.ComposeLocation{}
+	for _, project := range projects {
+		if project.WorkingDir != "" {
+			locations[project.Name] = config.ComposeLocation{WorkingDir: project.WorkingDir, ConfigFiles: project.ConfigFiles}
+		}
+	}
+	config.RecordComposeProjects(server.ServerName, locations)
+
+	for name, location := range config.History(server.ServerName).Compose {
+		if slices.ContainsFunc(projects, func(p remotessh.ComposeProject) bool { return p.Name == name }) {
+			continue
+		}
+		projects = append(projects, remotessh.ComposeProject{Name: name, WorkingDir: location.WorkingDir, ConfigFiles: location.ConfigFiles})
+	}
+	slices.SortFunc(projects, func(a, b remotessh.ComposeProject) int { return strings.Compare(a.Name, b.Name) })
+	return projects
+}
+
+func printComposeProjects(server models.Server, projects []remotessh.ComposeProject) {
+	if len(projects) == 0 {
+		fmt.Printf("No compose projects on %s\n", server.ServerName)
+		return
+	}
+
+	rows := make([][]string, len(projects))
+	for i, project := range projects {
+		style := okStyle
+		switch {
+		case project.Running == 0:
+			style = failedStyle
+		case project.Running < project.Total:
+			style = warnStyle
+		}
+		rows[i] = []string{
+			project.Name,
+			project.WorkingDir,
+			strings.Join(project.Services, ", "),
+			style.Render(fmt.Sprintf("%d/%d", project.Running, project.Total)),
+		}
+	}
+
+	t := newTable(-1, []string{"PROJECT", "DIRECTORY", "SERVICES", "RUNNING"}, rows)
+
+	fmt.Println(t.Render())
+}
+
+func init() {
+	// 프로젝트 이름 뒤의 플래그는 compose로 그대로 전달
+	composeCmd.Flags().SetInterspersed(false)
+	composeCmd.Flags().StringVar(&composeDir, "dir", "", "Project directory on the server, for projects without containers")
+	composeCmd.Flags().StringArrayVarP(&composeFiles, "file", "f", nil, "Compose file to use with --dir (repeatable)")
+	rootCmd.AddCommand(composeCmd)
+}
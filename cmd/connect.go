@@ -1,9 +1,8 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"os"
-	"os/exec"
 	"remotelink/config"
 	"remotelink/models"
 	remotessh "remotelink/ssh"
@@ -23,77 +22,92 @@ var connectCmd = &cobra.Command{
 			return nil
 		}
 
-		var selectedServer models.Server
-
-		// 1단계: 서버 선택
+		// 인자로 서버 이름 받음
 		if len(args) > 0 {
-			// 인자로 서버 이름 받음
-			serverName := args[0]
-			found := false
-			for _, server := range config.Servers {
-				if server.ServerName == serverName {
-					selectedServer = server
-					found = true
-					break
-				}
-			}
-			if !found {
-				return fmt.Errorf("server '%s' not found", serverName)
-			}
-		} else {
-			// 대화형으로 서버 선택
-			if len(config.Servers) == 1 {
-				selectedServer = config.Servers[0]
-			} else {
-				var err error
-				selectedServer, err = SelectServer()
-				if err != nil {
-					return err
-				}
+			server, err := findServer(args[0])
+			if err != nil {
+				return err
 			}
+			return selectTarget(server)
 		}
 
-		// 2단계: 호스트 또는 컨테이너 선택
-		return selectTarget(selectedServer)
+		// 대화형으로 서버 선택: 피커에서 호스트/컨테이너를 바로 고를 수 있음
+		server, container, chosen, err := selectServerTarget()
+		if err != nil {
+			return err
+		}
+		if !chosen {
+			return selectTarget(server)
+		}
+		if container != "" {
+			return connectToContainer(server, container)
+		}
+		return connectToServer(server)
 	},
 }
 
+// SelectServer asks for one of the servers matching --group and --tag with the fuzzy picker.
+// A single candidate is returned without asking.
 func SelectServer() (models.Server, error) {
-	options := make([]huh.Option[int], len(config.Servers))
-	for i, server := range config.Servers {
-		label := fmt.Sprintf("%-20s %s@%s:%d",
-			server.ServerName,
-			server.Username,
-			server.HostIp,
-			server.Port)
-
-		// 컨테이너 개수 표시
-		if len(server.Containers) > 0 {
-			label += fmt.Sprintf(" 🐳 %d containers", len(server.Containers))
-		}
-
-		options[i] = huh.NewOption(label, i)
+	servers, err := candidateServers()
+	if err != nil {
+		return models.Server{}, err
+	}
+	if len(servers) == 0 {
+		return models.Server{}, fmt.Errorf("no servers configured")
+	}
+	if len(servers) == 1 {
+		return servers[0], nil
 	}
 
-	var selectedIndex int
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewSelect[int]().
-				Title("🔌 Select server").
-				Description("Choose a server to connect").
-				Options(options...).
-				Value(&selectedIndex),
-		),
-	)
-
-	if err := form.Run(); err != nil {
+	result, err := runPicker("🔌 Select server", servers, false)
+	if err != nil {
 		return models.Server{}, err
 	}
+	return result.Server, nil
+}
+
+// selectServerTarget asks for a server like SelectServer, but lets the user open the host
+// or jump to a container from the picker. chosen is false when only the server was picked.
+func selectServerTarget() (server models.Server, container string, chosen bool, err error) {
+	servers, err := candidateServers()
+	if err != nil {
+		return models.Server{}, "", false, err
+	}
+	if len(servers) == 0 {
+		return models.Server{}, "", false, fmt.Errorf("no servers configured")
+	}
+	if len(servers) == 1 {
+		return servers[0], "", false, nil
+	}
 
-	return config.Servers[selectedIndex], nil
+	result, err := runPicker("🔌 Select server", servers, true)
+	if err != nil {
+		return models.Server{}, "", false, err
+	}
+	return result.Server, result.Container, result.Target, nil
 }
 
 func selectTarget(server models.Server) error {
+	container, err := selectContainer(server, fmt.Sprintf("📍 Select connection target for %s", server.ServerName))
+	if err != nil {
+		return err
+	}
+
+	if container != "" {
+		return connectToContainer(server, container)
+	}
+	return connectToServer(server)
+}
+
+// selectContainer asks whether to use the host, one of its running containers or a pod container.
+// It returns "" for the host, including when containers cannot be listed.
+func selectContainer(server models.Server, title string) (string, error) {
+	// 스피너 실행 전에 호스트 키 확인
+	if err := remotessh.TrustHostKey(server); err != nil {
+		return "", err
+	}
+
 	// 실시간으로 컨테이너 목록 조회
 	var containers []models.Container
 	var fetchErr error
@@ -101,105 +115,100 @@ func selectTarget(server models.Server) error {
 	err := spinner.New().
 		Title(fmt.Sprintf("Fetching containers from %s...", server.ServerName)).
 		Action(func() {
-			containers, fetchErr = remotessh.FetchContainers(server)
+			containers, fetchErr = remotessh.FetchTargets(server, false)
 		}).
 		Run()
 
 	if err != nil {
-		return err
-	}
-
-	if fetchErr != nil {
-		fmt.Printf("⚠️  Could not fetch containers: %v\n", fetchErr)
-		fmt.Println("   Connecting to host directly...")
-		return connectToServer(server)
+		return "", err
 	}
 
-	// 접속 대상 목록 생성: Host + Containers
-	type target struct {
-		label       string
-		isContainer bool
-		name        string
+	// 호스트 키 불일치는 호스트 직접 접속으로 넘어가지 않음
+	if errors.Is(fetchErr, remotessh.ErrHostKeyMismatch) {
+		return "", fmt.Errorf("❌ %w", fetchErr)
 	}
 
-	targets := []target{
-		{
-			label:       fmt.Sprintf("🖥️  %s (Host)", server.ServerName),
-			isContainer: false,
-			name:        "",
-		},
+	if fetchErr == nil {
+		config.RecordContainers(server.ServerName, containers)
 	}
 
-	// 실시간 조회된 컨테이너 추가
-	for _, container := range containers {
-		targets = append(targets, target{
-			label:       fmt.Sprintf("🐳 %s (%s)", container.ContainerName, container.ImageName),
-			isContainer: true,
-			name:        container.ContainerName,
-		})
+	if fetchErr != nil {
+		fmt.Printf("⚠️  Could not fetch containers: %v\n", fetchErr)
+		fmt.Println("   Using host directly...")
+		return "", nil
 	}
 
-	// 컨테이너가 없으면 바로 호스트 접속
+	// 컨테이너가 없으면 바로 호스트 사용
 	if len(containers) == 0 {
-		fmt.Println("No running containers found. Connecting to host...")
-		return connectToServer(server)
+		fmt.Println("No running containers found. Using host...")
+		return "", nil
 	}
 
-	// 선택 옵션 생성
-	options := make([]huh.Option[int], len(targets))
-	for i, t := range targets {
-		options[i] = huh.NewOption(t.label, i)
+	// 대상 목록 생성: Host + Containers + Pods
+	options := []huh.Option[string]{
+		huh.NewOption(fmt.Sprintf("🖥️  %s (Host)", server.ServerName), ""),
 	}
+	options = append(options, targetOptions(containers)...)
 
-	var selectedIndex int
+	var selected string
 	form := huh.NewForm(
 		huh.NewGroup(
-			huh.NewSelect[int]().
-				Title(fmt.Sprintf("📍 Select connection target for %s", server.ServerName)).
+			huh.NewSelect[string]().
+				Title(title).
 				Description("Choose host or container").
 				Options(options...).
-				Value(&selectedIndex),
+				Value(&selected),
 		),
 	)
 
 	if err := form.Run(); err != nil {
-		return err
+		return "", err
 	}
 
-	selectedTarget := targets[selectedIndex]
+	return selected, nil
+}
 
-	if selectedTarget.isContainer {
-		return connectToContainer(server, selectedTarget.name)
+// targetOptions lists containers for a picker, grouped by compose project, followed by pod
+// containers grouped by namespace. Option values are container references.
+func targetOptions(containers []models.Container) []huh.Option[string] {
+	var options []huh.Option[string]
+	runtimeContainers, pods := remotessh.SplitPods(containers)
+
+	projects, byProject := remotessh.GroupContainers(runtimeContainers)
+	for _, project := range projects {
+		for _, container := range byProject[project] {
+			label := fmt.Sprintf("🐳 %s (%s)", container.ContainerName, container.ImageName)
+			if project != "" {
+				label = fmt.Sprintf("🐳 %s/%s  %s (%s)", project, container.Service, container.ContainerName, container.ImageName)
+			}
+			options = append(options, huh.NewOption(label, container.Ref()))
+		}
 	}
-	return connectToServer(server)
+
+	namespaces, byNamespace := remotessh.GroupPods(pods)
+	for _, namespace := range namespaces {
+		for _, container := range byNamespace[namespace] {
+			label := fmt.Sprintf("☸️  %s/%s  %s (%s)", namespace, container.Pod, container.ContainerName, container.ImageName)
+			options = append(options, huh.NewOption(label, container.Ref()))
+		}
+	}
+	return options
 }
 
 func connectToServer(server models.Server) error {
+	config.RecordServerUse(server.ServerName)
+
 	fmt.Printf("\n🔌 Connecting to %s (%s@%s)...\n\n",
 		server.ServerName,
 		server.Username,
 		server.HostIp)
 
-	sshArgs := []string{
-		"-p", fmt.Sprintf("%d", server.Port),
-	}
-
-	if server.KeyPath != "" {
-		sshArgs = append(sshArgs, "-i", server.KeyPath)
-	}
-
-	sshArgs = append(sshArgs, fmt.Sprintf("%s@%s", server.Username, server.HostIp))
-
+	var command string
 	if server.DefaultPath != "" {
-		sshArgs = append(sshArgs, "-t", fmt.Sprintf("cd %s && exec $SHELL -l", server.DefaultPath))
+		command = fmt.Sprintf("cd %s && exec $SHELL -l", server.DefaultPath)
 	}
 
-	sshCmd := exec.Command("ssh", sshArgs...)
-	sshCmd.Stdin = os.Stdin
-	sshCmd.Stdout = os.Stdout
-	sshCmd.Stderr = os.Stderr
-
-	if err := sshCmd.Run(); err != nil {
+	if err := remotessh.Interactive(server, command); err != nil {
 		return fmt.Errorf("❌ connection failed: %w", err)
 	}
 
@@ -208,30 +217,15 @@ func connectToServer(server models.Server) error {
 }
 
 func connectToContainer(server models.Server, containerName string) error {
+	config.RecordServerUse(server.ServerName)
+
 	fmt.Printf("\n🐳 Connecting to container '%s' on %s...\n\n",
 		containerName,
 		server.ServerName)
 
-	sshArgs := []string{
-		"-p", fmt.Sprintf("%d", server.Port),
-	}
-
-	if server.KeyPath != "" {
-		sshArgs = append(sshArgs, "-i", server.KeyPath)
-	}
-
-	sshArgs = append(sshArgs,
-		"-t",
-		fmt.Sprintf("%s@%s", server.Username, server.HostIp),
-		fmt.Sprintf("docker exec -it %s /bin/bash || docker exec -it %s /bin/sh", containerName, containerName),
-	)
-
-	sshCmd := exec.Command("ssh", sshArgs...)
-	sshCmd.Stdin = os.Stdin
-	sshCmd.Stdout = os.Stdout
-	sshCmd.Stderr = os.Stderr
+	command := remotessh.ContainerShellCommand(server, containerName)
 
-	if err := sshCmd.Run(); err != nil {
+	if err := remotessh.Interactive(server, command); err != nil {
 		return fmt.Errorf("❌ connection failed: %w", err)
 	}
 
@@ -240,5 +234,6 @@ func connectToContainer(server models.Server, containerName string) error {
 }
 
 func init() {
+	addSelectorFlags(connectCmd)
 	rootCmd.AddCommand(connectCmd)
 }
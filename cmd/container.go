@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"fmt"
+	"remotelink/config"
+	"remotelink/models"
+	remotessh "remotelink/ssh"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var (
+	containerYes   bool
+	containerForce bool
+)
+
+var containerCmd = &cobra.Command{
+	Use:     "container",
+	Aliases: []string{"ctr"},
+	Short:   "List, start, stop, restart and remove containers on a server",
+	Long: `List, start, stop, restart and remove containers on a server.
+
+Without container names a picker lists the containers the action applies to,
+stopped ones included, and several can be selected at once.
+stop and rm ask for confirmation unless --yes is given.
+
+  remotelink container ls prod
+  remotelink container restart prod web worker
+  remotelink container rm prod --force`,
+}
+
+var containerListCmd = &cobra.Command{
+	Use:          "ls [server]",
+	Short:        "List all containers on a server",
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		server, err := containerServer(args)
+		if err != nil {
+			return err
+		}
+		containers, err := fetchAllContainers(server)
+		if err != nil {
+			return err
+		}
+		if len(containers) == 0 {
+			fmt.Printf("No containers on %s\n", server.ServerName)
+			return nil
+		}
+
+		rows := make([][]string, len(containers))
+		for i, c := range containers {
+			rows[i] = []string{c.ContainerName, c.ImageName, containerStateStyle(c.State).Render(c.State), c.Status}
+		}
+		t := newTable(-1, []string{"NAME", "IMAGE", "STATE", "STATUS"}, rows)
+
+		fmt.Println(t.Render())
+		return nil
+	},
+}
+
+// containerAction describes one lifecycle subcommand.
+type containerAction struct {
+	action string
+	short  string
+	verb   string // "Stopping"
+	done   string // "stopped"
+	// confirm is the question asked before a destructive action, empty for none.
+	confirm string
+	// applies picks the containers offered by the picker.
+	applies func(models.Container) bool
+}
+
+var containerActions = []containerAction{
+	{
+		action: remotessh.ContainerStart, short: "Start stopped containers",
+		verb: "Starting", done: "started",
+		applies: func(c models.Container) bool { return !containerRunning(c) },
+	},
+	{
+		action: remotessh.ContainerStop, short: "Stop running containers",
+		verb: "Stopping", done: "stopped", confirm: "Stop",
+		applies: containerRunning,
+	},
+	{
+		action: remotessh.ContainerRestart, short: "Restart containers",
+		verb: "Restarting", done: "restarted",
+		applies: func(c models.Container) bool { return true },
+	},
+	{
+		action: remotessh.ContainerRemove, short: "Remove containers",
+		verb: "Removing", done: "removed", confirm: "Remove",
+		// 실행 중인 컨테이너는 --force일 때만 목록에 표시
+		applies: func(c models.Container) bool { return containerForce || !containerRunning(c) },
+	},
+}
+
+func newContainerActionCmd(a containerAction) *cobra.Command {
+	return &cobra.Command{
+		Use:          a.action + " [server] [container...]",
+		Short:        a.short,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			server, err := containerServer(args)
+			if err != nil {
+				return err
+			}
+
+			var names []string
+			if len(args) > 1 {
+				names = args[1:]
+			} else {
+				names, err = pickContainers(server, a)
+				if err != nil || len(names) == 0 {
+					return err
+				}
+			}
+
+			if a.confirm != "" && !containerYes {
+				confirm := false
+				err := huh.NewConfirm().
+					Title(fmt.Sprintf("%s %s on %s?", a.confirm, strings.Join(names, ", "), server.ServerName)).
+					Value(&confirm).
+					Run()
+				if err != nil {
+					return err
+				}
+				if !confirm {
+					fmt.Println("Cancelled")
+					return nil
+				}
+			}
+
+			return runContainerAction(server, a, names)
+		},
+	}
+}
+
+// containerServer resolves the server argument, or asks for one.
+func containerServer(args []string) (models.Server, error) {
+	var server models.Server
+	var err error
+	if len(args) > 0 {
+		server, err = findServer(args[0])
+	} else {
+		server, err = SelectServer()
+	}
+	if err != nil {
+		return server, err
+	}
+	return server, remotessh.TrustHostKey(server)
+}
+
+// fetchAllContainers lists every container with a spinner, remembering the running ones for the picker.
+func fetchAllContainers(server models.Server) ([]models.Container, error) {
+	var containers []models.Container
+	var fetchErr error
+	err := spinner.New().
+		Title(fmt.Sprintf("Fetching containers from %s...", server.ServerName)).
+		Action(func() {
+			containers, fetchErr = remotessh.FetchContainers(server, true)
+		}).
+		Run()
+	if err != nil {
+		return nil, err
+	}
+	if fetchErr != nil {
+		return nil, fmt.Errorf("❌ %w", fetchErr)
+	}
+
+	var running []models.Container
+	for _, c := range containers {
+		if containerRunning(c) {
+			running = append(running, c)
+		}
+	}
+	config.RecordContainers(server.ServerName, running)
+	return containers, nil
+}
+
+// pickContainers offers the containers an action applies to in a filterable multi-select.
+func pickContainers(server models.Server, a containerAction) ([]string, error) {
+	containers, err := fetchAllContainers(server)
+	if err != nil {
+		return nil, err
+	}
+
+	var options []huh.Option[string]
+	for _, c := range containers {
+		if a.applies(c) {
+			label := fmt.Sprintf("%-24s %-28s %s", c.ContainerName, c.ImageName, containerStateStyle(c.State).Render(c.Status))
+			options = append(options, huh.NewOption(label, c.ContainerName))
+		}
+	}
+	if len(options) == 0 {
+		fmt.Printf("No containers on %s to %s\n", server.ServerName, a.action)
+		return nil, nil
+	}
+
+	var names []string
+	err = huh.NewMultiSelect[string]().
+		Title(fmt.Sprintf("Select containers to %s on %s", a.action, server.ServerName)).
+		Options(options...).
+		Filterable(true).
+		Value(&names).
+		Run()
+	return names, err
+}
+
+// runContainerAction applies the action to every container at once and reports each result.
+func runContainerAction(server models.Server, a containerAction, names []string) error {
+	errs := make([]error, len(names))
+	err := spinner.New().
+		Title(fmt.Sprintf("%s %d container(s) on %s...", a.verb, len(names), server.ServerName)).
+		Action(func() {
+			var wg sync.WaitGroup
+			for i, name := range names {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs[i] = remotessh.ContainerAction(server, a.action, name, containerForce)
+				}()
+			}
+			wg.Wait()
+		}).
+		Run()
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for i, name := range names {
+		if errs[i] != nil {
+			failed++
+			fmt.Println(failedStyle.Render(fmt.Sprintf("✗ %s: %s", name, firstLine(errs[i].Error()))))
+			continue
+		}
+		fmt.Println(okStyle.Render(fmt.Sprintf("✓ %s %s", name, a.done)))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d containers failed", failed, len(names))
+	}
+	return nil
+}
+
+// containerRunning reports whether the container is up, paused or not.
+func containerRunning(c models.Container) bool {
+	return c.State == remotessh.StateRunning || c.State == remotessh.StatePaused || c.State == remotessh.StateRestarting
+}
+
+func containerStateStyle(state string) lipgloss.Style {
+	switch state {
+	case remotessh.StateRunning:
+		return okStyle
+	case remotessh.StateExited, remotessh.StateDead:
+		return failedStyle
+	}
+	return warnStyle
+}
+
+func init() {
+	containerCmd.AddCommand(containerListCmd)
+	for _, a := range containerActions {
+		actionCmd := newContainerActionCmd(a)
+		if a.confirm != "" {
+			actionCmd.Flags().BoolVarP(&containerYes, "yes", "y", false, "Do not ask for confirmation")
+		}
+		if a.action == remotessh.ContainerRemove {
+			actionCmd.Flags().BoolVarP(&containerForce, "force", "f", false, "Remove running containers too")
+		}
+		containerCmd.AddCommand(actionCmd)
+	}
+	rootCmd.AddCommand(containerCmd)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"remotelink/config"
+	remotessh "remotelink/ssh"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+var cpCmd = &cobra.Command{
+	Use:   "cp <server[/container]:path> <server[/container]:path>",
+	Short: "Copy a file or directory from one server to another",
+	Long: `Copy a file or directory from one server (or container) to another.
+
+The data is streamed through this machine, so the servers do not need to reach
+each other and nothing is staged locally. An existing destination directory
+receives the source by name, as with scp.
+
+  remotelink cp prod:/backups/db.sql staging:/tmp/
+  remotelink cp prod/web:/app/uploads staging/web:/app/`,
+	Aliases:      []string{"copy"},
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(config.Servers) == 0 {
+			fmt.Println("❌ No servers configured")
+			return nil
+		}
+
+		src, err := copyEndpoint(args[0])
+		if err != nil {
+			return err
+		}
+		dst, err := copyEndpoint(args[1])
+		if err != nil {
+			return err
+		}
+		if src.Path == "" {
+			return fmt.Errorf("a source path is required, e.g. %s:/path", src.Server.ServerName)
+		}
+
+		// 두 서버 모두 호스트 키를 먼저 확인
+		for _, target := range []remoteTarget{src, dst} {
+			if err := remotessh.TrustHostKey(target.Server); err != nil {
+				return err
+			}
+		}
+
+		fmt.Printf("\n🔀 Copying %s → %s\n\n", src, dst)
+
+		start := time.Now()
+		var copied int64
+		err = runTransfer(remotessh.TransferOptions{}, func(ctx context.Context, opts remotessh.TransferOptions) error {
+			var err error
+			copied, err = remotessh.Copy(ctx,
+				remotessh.Endpoint{Server: src.Server, Container: src.Container, Path: src.Path},
+				remotessh.Endpoint{Server: dst.Server, Container: dst.Container, Path: dst.Path},
+				opts)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("\n✅ Copy complete (%s in %s)\n", humanize.Bytes(uint64(copied)), time.Since(start).Round(time.Millisecond))
+		return nil
+	},
+}
+
+// copyEndpoint resolves a server[/container]:path argument; cp has no local side.
+func copyEndpoint(spec string) (remoteTarget, error) {
+	target, ok, err := parseRemoteTarget(spec)
+	if err != nil {
+		return remoteTarget{}, err
+	}
+	if !ok {
+		return remoteTarget{}, fmt.Errorf("'%s' is not server:path for a configured server; use send or pull for local files", spec)
+	}
+	if target.Path == "" {
+		return remoteTarget{}, fmt.Errorf("'%s' has no path", spec)
+	}
+	return target, nil
+}
+
+func init() {
+	rootCmd.AddCommand(cpCmd)
+}
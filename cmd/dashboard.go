@@ -0,0 +1,402 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"remotelink/config"
+	"remotelink/models"
+	remotessh "remotelink/ssh"
+	"strings"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+var dashboardInterval time.Duration
+
+// dashboardFetchLimit caps concurrent metric probes so large fleets do not open every connection at once.
+const dashboardFetchLimit = 16
+
+var dashboardHelpStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262"))
+
+var dashboardCmd = &cobra.Command{
+	Use:     "dashboard",
+	Short:   "Live dashboard of every server",
+	Aliases: []string{"dash", "top"},
+	Long: `Show a live, full-screen view of every server with reachability, load, memory,
+disk usage and running containers, refreshed in the background.
+
+Keys: ↑/↓ move · enter connect · s container shell · l tail logs · x run command · r refresh · q quit`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		servers, err := candidateServers()
+		if err != nil {
+			return err
+		}
+		if len(servers) == 0 {
+			fmt.Println("No servers configured. Use 'remotelink add' to add a server.")
+			return nil
+		}
+
+		// 대시보드 안에서는 프롬프트를 띄울 수 없으므로 호스트 키를 먼저 확인
+		rows := make([]dashboardRow, len(servers))
+		for i, server := range servers {
+			err := remotessh.TrustHostKey(server)
+			rows[i] = dashboardRow{server: server, err: err, trustFailed: err != nil, loading: err == nil}
+		}
+
+		model := dashboardModel{rows: rows, interval: dashboardInterval, fetchSlots: make(chan struct{}, dashboardFetchLimit)}
+		_, err = tea.NewProgram(model, tea.WithAltScreen()).Run()
+		return err
+	},
+}
+
+type dashboardRow struct {
+	server  models.Server
+	metrics remotessh.Metrics
+	err     error
+	updated time.Time
+	loading bool
+	// trustFailed rows are never probed; their host key has to be sorted out first.
+	trustFailed bool
+}
+
+type dashboardModel struct {
+	rows     []dashboardRow
+	cursor   int
+	offset   int
+	width    int
+	height   int
+	interval time.Duration
+	status   string
+
+	fetchSlots chan struct{}
+}
+
+type metricsMsg struct {
+	index   int
+	metrics remotessh.Metrics
+	err     error
+}
+
+type dashboardTickMsg struct{}
+
+type actionDoneMsg struct {
+	err error
+}
+
+func (m dashboardModel) Init() tea.Cmd {
+	cmds := []tea.Cmd{m.tick()}
+	for i, row := range m.rows {
+		if row.loading {
+			cmds = append(cmds, m.fetch(i))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m dashboardModel) tick() tea.Cmd {
+	return tea.Tick(m.interval, func(time.Time) tea.Msg { return dashboardTickMsg{} })
+}
+
+// refreshAll starts a probe for every row that is not already loading.
+func (m *dashboardModel) refreshAll() tea.Cmd {
+	var cmds []tea.Cmd
+	for i := range m.rows {
+		if m.rows[i].loading || m.rows[i].trustFailed {
+			continue
+		}
+		m.rows[i].loading = true
+		cmds = append(cmds, m.fetch(i))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m dashboardModel) fetch(index int) tea.Cmd {
+	server := m.rows[index].server
+	slots := m.fetchSlots
+	return func() tea.Msg {
+		slots <- struct{}{}
+		defer func() { <-slots }()
+		metrics, err := remotessh.FetchMetrics(server)
+		return metricsMsg{index: index, metrics: metrics, err: err}
+	}
+}
+
+func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.clampOffset()
+
+	case metricsMsg:
+		row := &m.rows[msg.index]
+		row.loading = false
+		row.err = msg.err
+		row.updated = time.Now()
+		if msg.err == nil {
+			row.metrics = msg.metrics
+		}
+
+	case dashboardTickMsg:
+		return m, tea.Batch(m.refreshAll(), m.tick())
+
+	case actionDoneMsg:
+		m.status = ""
+		if msg.err != nil && !errors.Is(msg.err, huh.ErrUserAborted) {
+			m.status = errorStyle.Render(msg.err.Error())
+		}
+		return m, m.refreshAll()
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "up", "k":
+			m.cursor = max(m.cursor-1, 0)
+			m.clampOffset()
+		case "down", "j":
+			m.cursor = min(m.cursor+1, len(m.rows)-1)
+			m.clampOffset()
+		case "r":
+			return m, m.refreshAll()
+		case "enter", "c":
+			server := m.rows[m.cursor].server
+			return m, runAction(func() error { return connectToServer(server) })
+		case "s":
+			server := m.rows[m.cursor].server
+			return m, runAction(func() error { return containerShell(server) })
+		case "l":
+			server := m.rows[m.cursor].server
+			return m, runAction(func() error { return tailLogs(server) })
+		case "x":
+			server := m.rows[m.cursor].server
+			return m, runAction(func() error { return quickCommand(server) })
+		}
+	}
+	return m, nil
+}
+
+// visibleRows is how many table rows fit next to the header, detail panel and help.
+func (m dashboardModel) visibleRows() int {
+	if m.height == 0 {
+		return len(m.rows)
+	}
+	return max(m.height-20, 3)
+}
+
+func (m *dashboardModel) clampOffset() {
+	visible := m.visibleRows()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+visible {
+		m.offset = m.cursor - visible + 1
+	}
+}
+
+func (m dashboardModel) View() string {
+	var b strings.Builder
+
+	up := 0
+	for _, row := range m.rows {
+		if row.err == nil && !row.updated.IsZero() {
+			up++
+		}
+	}
+	b.WriteString(titleStyle.Render(fmt.Sprintf("remotelink dashboard — %d/%d servers up · refresh every %s", up, len(m.rows), m.interval)))
+	b.WriteString("\n")
+
+	end := min(m.offset+m.visibleRows(), len(m.rows))
+	var rows [][]string
+	for i := m.offset; i < end; i++ {
+		rows = append(rows, m.rows[i].cells())
+	}
+
+	t := newTable(m.cursor-m.offset, []string{"SERVER", "GROUP", "STATUS", "LATENCY", "LOAD", "MEMORY", "DISK", "CONTAINERS"}, rows)
+	b.WriteString(t.Render())
+	b.WriteString("\n")
+
+	if len(m.rows) > m.visibleRows() {
+		b.WriteString(dashboardHelpStyle.Render(fmt.Sprintf("  %d/%d", m.cursor+1, len(m.rows))))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(m.rows[m.cursor].detail())
+	b.WriteString("\n")
+
+	if m.status != "" {
+		b.WriteString(m.status + "\n")
+	}
+	b.WriteString(dashboardHelpStyle.Render("↑/↓ move • enter connect • s container shell • l tail logs • x run command • r refresh • q quit"))
+	return b.String()
+}
+
+// cells renders the row's table columns.
+func (row dashboardRow) cells() []string {
+	status := valueStyle.Render("…")
+	latency, load, memory, disk, containers := "-", "-", "-", "-", "-"
+
+	switch {
+	case row.err != nil:
+		status = failedStyle.Render("● down")
+	case !row.updated.IsZero():
+		metrics := row.metrics
+		status = okStyle.Render("● up")
+		latency = formatLatency(metrics.Latency)
+		load = fmt.Sprintf("%.2f %.2f %.2f", metrics.Load1, metrics.Load5, metrics.Load15)
+		memory = usageBar(metrics.MemUsedPercent())
+		disk = usageBar(metrics.DiskUsedPercent())
+		containers = "n/a"
+		if metrics.Containers >= 0 {
+			containers = fmt.Sprintf("%d", metrics.Containers)
+		}
+	}
+	if row.loading && row.updated.IsZero() {
+		status = valueStyle.Render("… checking")
+	}
+
+	group := row.server.Group
+	if group == "" {
+		group = "-"
+	}
+	return []string{row.server.ServerName, group, status, latency, load, memory, disk, containers}
+}
+
+// detail renders the highlighted server's box, in the same style as "ls".
+func (row dashboardRow) detail() string {
+	server := row.server
+	var info string
+	info += labelStyle.Render("Server Name") + "  " + valueStyle.Render(server.ServerName) + "\n"
+	info += labelStyle.Render("Host") + "  " + valueStyle.Render(fmt.Sprintf("%s@%s:%d", server.Username, server.HostIp, server.Port)) + "\n"
+	if server.Jump != "" {
+		info += labelStyle.Render("Jump Host") + "  " + valueStyle.Render(server.Jump) + "\n"
+	}
+	if len(server.Tags) > 0 {
+		info += labelStyle.Render("Tags") + "  " + valueStyle.Render(strings.Join(server.Tags, ", ")) + "\n"
+	}
+
+	switch {
+	case row.err != nil:
+		info += errorStyle.Render(firstLine(row.err.Error()))
+	case !row.updated.IsZero():
+		metrics := row.metrics
+		info += labelStyle.Render("Memory") + "  " + valueStyle.Render(fmt.Sprintf("%s / %s",
+			humanize.IBytes(metrics.MemTotal-metrics.MemAvailable), humanize.IBytes(metrics.MemTotal))) + "\n"
+		info += labelStyle.Render("Disk (/)") + "  " + valueStyle.Render(fmt.Sprintf("%s / %s",
+			humanize.IBytes(metrics.DiskUsed), humanize.IBytes(metrics.DiskTotal))) + "\n"
+		info += labelStyle.Render("Updated") + "  " + valueStyle.Render(row.updated.Format("15:04:05"))
+	default:
+		info += valueStyle.Render("Checking...")
+	}
+
+	return serverInfoStyle.Render(info)
+}
+
+// usageBar renders a percentage as a small bar, colored by how full it is.
+func usageBar(percent float64) string {
+	const width = 10
+	filled := min(int(percent/100*width+0.5), width)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+
+	style := okStyle
+	switch {
+	case percent >= 90:
+		style = failedStyle
+	case percent >= 70:
+		style = warnStyle
+	}
+	return style.Render(fmt.Sprintf("%s %3.0f%%", bar, percent))
+}
+
+// runAction suspends the dashboard, runs fn with the terminal, and resumes.
+func runAction(fn func() error) tea.Cmd {
+	return tea.Exec(actionCommand(fn), func(err error) tea.Msg { return actionDoneMsg{err: err} })
+}
+
+// actionCommand adapts a function to tea.ExecCommand. fn uses the process's own stdio.
+type actionCommand func() error
+
+func (a actionCommand) Run() error        { return a() }
+func (actionCommand) SetStdin(io.Reader)  {}
+func (actionCommand) SetStdout(io.Writer) {}
+func (actionCommand) SetStderr(io.Writer) {}
+
+// containerShell asks for a running container on server and opens a shell in it.
+func containerShell(server models.Server) error {
+	container, err := selectContainer(server, fmt.Sprintf("🐳 Open a shell on %s", server.ServerName))
+	if err != nil {
+		return err
+	}
+	if container == "" {
+		return connectToServer(server)
+	}
+	return connectToContainer(server, container)
+}
+
+// tailLogs follows a container's logs, or the host's system log, until interrupted.
+func tailLogs(server models.Server) error {
+	container, err := selectContainer(server, fmt.Sprintf("📜 Tail logs on %s", server.ServerName))
+	if err != nil {
+		return err
+	}
+
+	source := remotessh.LogSource{Container: container}
+	if container == "" {
+		if source, err = remotessh.SystemLogSource(server); err != nil {
+			return err
+		}
+	}
+	config.RecordServerUse(server.ServerName)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("\n📜 Following logs on %s (Ctrl+C to return)\n\n", server.ServerName)
+	err = remotessh.StreamLogs(ctx, server, source, remotessh.LogOptions{Tail: "100", Follow: true}, os.Stdout, os.Stderr)
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// quickCommand asks for a command, runs it on server and waits for enter before returning.
+func quickCommand(server models.Server) error {
+	var command string
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title(fmt.Sprintf("⚡ Run on %s", server.ServerName)).
+				Value(&command).
+				Placeholder("uptime"),
+		),
+	)
+	if err := form.Run(); err != nil || strings.TrimSpace(command) == "" {
+		return nil
+	}
+
+	fmt.Println()
+	err := remotessh.BackendFor(server).Stream(server, command, nil, os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Println(errorStyle.Render(err.Error()))
+	}
+
+	fmt.Print(dashboardHelpStyle.Render("\nPress enter to return to the dashboard"))
+	bufio.NewReader(os.Stdin).ReadString('\n')
+	return nil
+}
+
+func init() {
+	dashboardCmd.Flags().DurationVar(&dashboardInterval, "interval", 10*time.Second, "How often to refresh metrics")
+	addSelectorFlags(dashboardCmd)
+	rootCmd.AddCommand(dashboardCmd)
+}
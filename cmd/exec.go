@@ -0,0 +1,403 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"remotelink/config"
+	"remotelink/models"
+	remotessh "remotelink/ssh"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	execServers  []string
+	execAll      bool
+	execParallel int
+	execJSON     bool
+	execUser     string
+	execWorkdir  string
+	execEnv      []string
+)
+
+// prefixPalette colors the per-server output prefixes, cycling when there are more servers.
+var prefixPalette = []lipgloss.Color{"#7D56F4", "#04B575", "#F25D94", "#3C9EE7", "#F4A259", "#2EC4B6", "#E8E288"}
+
+var (
+	okStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575"))
+	failedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF4444"))
+	// warnStyle marks what is neither fine nor failed: degraded, pending or changed.
+	warnStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F4A259"))
+)
+
+// execResult is the outcome of running the command on one server. It is also the --json output.
+type execResult struct {
+	Server     string `json:"server"`
+	ExitCode   int    `json:"exit_code"`
+	DurationMs int64  `json:"duration_ms"`
+	Stdout     string `json:"stdout,omitempty"`
+	Stderr     string `json:"stderr,omitempty"`
+	Error      string `json:"error,omitempty"`
+	duration   time.Duration
+}
+
+var execCmd = &cobra.Command{
+	Use:          "exec [servers... | server/container] -- <command>",
+	Short:        "Run a command on many servers concurrently, or in a container",
+	SilenceUsage: true,
+	Long: `Run a command on many servers concurrently.
+
+Servers are given as arguments before --, with --servers, --group/--tag or --all; without
+any of them a picker is shown. Output lines are prefixed with the server name, followed by a summary.
+Arguments after -- are passed on as they are; a single argument is run by the remote shell
+instead, so it can hold pipes and variables.
+
+With a single server/container target (or server/pod/<namespace>/<pod>[/<container>]) the command
+runs inside that container instead, without a shell: stdin is passed through, a terminal is
+allocated only when stdin is one, and remotelink exits with the command's exit code.
+--user, --workdir and --env apply to container targets.
+
+  remotelink exec --servers web1,web2 -- uptime
+  remotelink exec --group web -- systemctl restart nginx
+  remotelink exec --all --json -- df -h / | jq .
+  remotelink exec --group web -- 'tail -n 100 /var/log/nginx/error.log | grep -c upstream'
+  cat dump.sql | remotelink exec db/pg -- psql -U app
+  remotelink exec prod/web -u www-data -w /srv -e DEBUG=1 -- ./manage.py check`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var names []string
+		commandArgs := args
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			names = args[:dash]
+			commandArgs = args[dash:]
+		}
+		if len(commandArgs) == 0 {
+			return fmt.Errorf("no command given; pass it after --")
+		}
+		if slices.ContainsFunc(names, func(name string) bool { return strings.Contains(name, "/") }) {
+			return execInContainer(cmd, names, commandArgs)
+		}
+		if execUser != "" || execWorkdir != "" || len(execEnv) > 0 {
+			return fmt.Errorf("--user, --workdir and --env need a server/container target")
+		}
+		command := remotessh.ShellCommand(commandArgs)
+
+		servers, err := execTargets(append(names, execServers...))
+		if err != nil {
+			return err
+		}
+		if len(servers) == 0 {
+			fmt.Println("No servers selected")
+			return nil
+		}
+
+		results := runOnServers(servers, command)
+
+		if !execJSON {
+			printExecSummary(results)
+		}
+
+		failed := 0
+		for _, result := range results {
+			if result.ExitCode != 0 {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d servers failed", failed, len(results))
+		}
+		return nil
+	},
+}
+
+// execInContainer runs args in the single server/container target named, attached to the
+// local terminal, and exits with the command's status.
+func execInContainer(cmd *cobra.Command, names, args []string) error {
+	if len(names) > 1 || len(execServers) > 0 || execAll || selectorActive() || execJSON {
+		return fmt.Errorf("a container command runs on a single server/container target")
+	}
+	serverName, container, _ := strings.Cut(names[0], "/")
+	if container == "" {
+		return fmt.Errorf("no container given in '%s'", names[0])
+	}
+	server, err := findServer(serverName)
+	if err != nil {
+		return err
+	}
+
+	command, err := remotessh.ContainerExecCommand(server, container, args, remotessh.ExecOptions{
+		User:    execUser,
+		Workdir: execWorkdir,
+		Env:     execEnv,
+		TTY:     term.IsTerminal(int(os.Stdin.Fd())),
+	})
+	if err != nil {
+		return err
+	}
+	if err := remotessh.TrustHostKey(server); err != nil {
+		return err
+	}
+	config.RecordServerUse(server.ServerName)
+
+	err = remotessh.Interactive(server, command)
+	if status, ok := remotessh.ExitStatus(err); ok {
+		// 명령의 출력은 이미 표시되었으므로 종료 코드만 전달
+		cmd.SilenceErrors = true
+		return exitError{code: status}
+	}
+	return err
+}
+
+// execTargets resolves the servers to run on from names, --all, --group/--tag or an interactive multi-select.
+func execTargets(names []string) ([]models.Server, error) {
+	if execAll {
+		return config.Servers, nil
+	}
+
+	var servers []models.Server
+	if selectorActive() {
+		matched, err := candidateServers()
+		if err != nil {
+			return nil, err
+		}
+		servers = matched
+	}
+
+	if len(names) > 0 || selectorActive() {
+		for _, name := range names {
+			server, err := findServer(name)
+			if err != nil {
+				return nil, err
+			}
+			if !slices.ContainsFunc(servers, func(s models.Server) bool { return s.ServerName == name }) {
+				servers = append(servers, server)
+			}
+		}
+		return servers, nil
+	}
+
+	if len(config.Servers) == 0 {
+		return nil, fmt.Errorf("no servers configured")
+	}
+
+	options := make([]huh.Option[int], len(config.Servers))
+	for i, server := range config.Servers {
+		options[i] = huh.NewOption(serverLabel(server), i)
+	}
+
+	var selected []int
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[int]().
+				Title("Select servers").
+				Options(options...).
+				Filterable(true).
+				Value(&selected),
+		),
+	)
+	if err := form.Run(); err != nil {
+		return nil, err
+	}
+
+	servers = make([]models.Server, len(selected))
+	for i, index := range selected {
+		servers[i] = config.Servers[index]
+	}
+	return servers, nil
+}
+
+// runOnServers runs command on every server with at most --parallel connections at once.
+// Results keep the order of servers.
+func runOnServers(servers []models.Server, command string) []execResult {
+	results := make([]execResult, len(servers))
+	ready := make([]bool, len(servers))
+
+	// 동시 실행 중에는 프롬프트를 띄울 수 없으므로 호스트 키를 먼저 확인
+	for i, server := range servers {
+		if err := remotessh.TrustHostKey(server); err != nil {
+			results[i] = execResult{Server: server.ServerName, ExitCode: -1, Error: err.Error()}
+			continue
+		}
+		ready[i] = true
+	}
+
+	width := 0
+	for _, server := range servers {
+		width = max(width, len(server.ServerName))
+	}
+
+	var outMu sync.Mutex
+	encoder := json.NewEncoder(os.Stdout)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < max(execParallel, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				style := lipgloss.NewStyle().Bold(true).Foreground(prefixPalette[i%len(prefixPalette)])
+				prefix := style.Render(fmt.Sprintf("%-*s |", width, servers[i].ServerName))
+				results[i] = runOnServer(servers[i], command, prefix, &outMu)
+
+				if execJSON {
+					outMu.Lock()
+					encoder.Encode(results[i])
+					outMu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := range servers {
+		if ready[i] {
+			jobs <- i
+		} else if execJSON {
+			outMu.Lock()
+			encoder.Encode(results[i])
+			outMu.Unlock()
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+func runOnServer(server models.Server, command, prefix string, outMu *sync.Mutex) execResult {
+	result := execResult{Server: server.ServerName}
+
+	var stdout, stderr io.Writer
+	var stdoutBuf, stderrBuf bytes.Buffer
+	var prefixers []*linePrefixer
+	if execJSON {
+		stdout, stderr = &stdoutBuf, &stderrBuf
+	} else {
+		outPrefixer := &linePrefixer{mu: outMu, out: os.Stdout, prefix: prefix}
+		errPrefixer := &linePrefixer{mu: outMu, out: os.Stderr, prefix: prefix}
+		prefixers = append(prefixers, outPrefixer, errPrefixer)
+		stdout, stderr = outPrefixer, errPrefixer
+	}
+
+	start := time.Now()
+	err := remotessh.BackendFor(server).Stream(server, command, nil, stdout, stderr)
+	result.duration = time.Since(start)
+	result.DurationMs = result.duration.Milliseconds()
+
+	for _, p := range prefixers {
+		p.Flush()
+	}
+	result.Stdout = stdoutBuf.String()
+	result.Stderr = stderrBuf.String()
+
+	if err != nil {
+		if status, ok := remotessh.ExitStatus(err); ok {
+			result.ExitCode = status
+		} else {
+			result.ExitCode = -1
+			result.Error = strings.TrimSpace(err.Error())
+		}
+	}
+	return result
+}
+
+func printExecSummary(results []execResult) {
+	rows := make([][]string, len(results))
+	for i, result := range results {
+		status := okStyle.Render("ok")
+		exitCode := fmt.Sprintf("%d", result.ExitCode)
+		switch {
+		case result.Error != "":
+			status = failedStyle.Render("error")
+			exitCode = "-"
+		case result.ExitCode != 0:
+			status = failedStyle.Render("failed")
+		}
+
+		duration := "-"
+		if result.duration > 0 {
+			duration = result.duration.Round(time.Millisecond).String()
+		}
+
+		rows[i] = []string{result.Server, status, exitCode, duration, firstLine(result.Error)}
+	}
+
+	t := newTable(-1, []string{"SERVER", "STATUS", "EXIT", "DURATION", "ERROR"}, rows)
+
+	fmt.Println()
+	fmt.Println(t.Render())
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// linePrefixer writes complete lines to out with a prefix, holding a partial line until Flush.
+// The mutex is shared between servers so lines never interleave mid-line.
+// Lines not matching filter are dropped when it is set.
+type linePrefixer struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	filter *regexp.Regexp
+	buf    []byte
+}
+
+func (w *linePrefixer) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes any trailing output that did not end with a newline.
+func (w *linePrefixer) Flush() {
+	if len(w.buf) > 0 {
+		w.emit(w.buf)
+		w.buf = nil
+	}
+}
+
+func (w *linePrefixer) emit(line []byte) {
+	line = bytes.TrimRight(line, "\r")
+	if w.filter != nil && !w.filter.Match(line) {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.prefix == "" {
+		fmt.Fprintf(w.out, "%s\n", line)
+		return
+	}
+	fmt.Fprintf(w.out, "%s %s\n", w.prefix, line)
+}
+
+func init() {
+	execCmd.Flags().StringSliceVar(&execServers, "servers", nil, "Comma-separated server names")
+	execCmd.Flags().BoolVar(&execAll, "all", false, "Run on every configured server")
+	execCmd.Flags().IntVarP(&execParallel, "parallel", "p", 8, "Maximum number of servers to run on at once")
+	execCmd.Flags().BoolVar(&execJSON, "json", false, "Print one JSON result object per server instead of prefixed output")
+	execCmd.Flags().StringVarP(&execUser, "user", "u", "", "User to run as in the container")
+	execCmd.Flags().StringVarP(&execWorkdir, "workdir", "w", "", "Working directory in the container")
+	execCmd.Flags().StringArrayVarP(&execEnv, "env", "e", nil, "Environment variable KEY=VALUE to set in the container (repeatable)")
+	addSelectorFlags(execCmd)
+	rootCmd.AddCommand(execCmd)
+}
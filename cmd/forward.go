@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"remotelink/config"
+	"remotelink/models"
+	remotessh "remotelink/ssh"
+	"strings"
+	"syscall"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+var (
+	forwardLocal     []string
+	forwardRemote    []string
+	forwardDynamic   []string
+	forwardContainer string
+)
+
+var forwardCmd = &cobra.Command{
+	Use:   "forward [server] [preset...]",
+	Short: "Forward ports through a server",
+	Long: `Forward ports through a server until interrupted.
+
+Forwards are given OpenSSH-style with -L, -R and -D, or by naming presets from the
+server's "forwards" config. With --container, -L targets a port on that container,
+reached through its IP address.
+
+  remotelink forward prod -L 5432:localhost:5432
+  remotelink forward prod -D 1080
+  remotelink forward prod -c web -L 8080:80
+  remotelink forward prod db`,
+	Aliases: []string{"fwd"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var server models.Server
+		var err error
+		if len(args) > 0 {
+			server, err = findServer(args[0])
+		} else {
+			server, err = SelectServer()
+		}
+		if err != nil {
+			return err
+		}
+
+		forwards, err := forwardsFromArgs(server, args)
+		if err != nil {
+			return err
+		}
+		if len(forwards) == 0 {
+			return fmt.Errorf("no forwards given; use -L, -R, -D or a preset name")
+		}
+
+		if err := remotessh.TrustHostKey(server); err != nil {
+			return err
+		}
+		resolved, err := remotessh.ResolveForwards(server, forwards)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("🔀 Forwarding through %s (%s@%s)\n", server.ServerName, server.Username, server.HostIp)
+		for _, forward := range resolved {
+			fmt.Printf("   %s\n", remotessh.DescribeForward(forward))
+		}
+		fmt.Println("\nPress Ctrl+C to stop")
+		config.RecordServerUse(server.ServerName)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if err := remotessh.Forward(ctx, server, resolved, remotessh.ForwardOptions{}); err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+
+		fmt.Println("\n✅ Forwarding stopped")
+		return nil
+	},
+}
+
+// forwardsFromArgs collects forwards from preset names after the server argument and the
+// -L/-R/-D flags. With neither, the server's presets are offered in a multi-select.
+func forwardsFromArgs(server models.Server, args []string) ([]models.Forward, error) {
+	var forwards []models.Forward
+
+	if len(args) > 1 {
+		for _, name := range args[1:] {
+			forward, found := findForwardPreset(server, name)
+			if !found {
+				return nil, fmt.Errorf("forward preset '%s' not found on %s%s", name, server.ServerName, presetHint(server))
+			}
+			forwards = append(forwards, forward)
+		}
+	}
+
+	specs := []struct {
+		forwardType string
+		values      []string
+	}{
+		{remotessh.ForwardLocal, forwardLocal},
+		{remotessh.ForwardRemote, forwardRemote},
+		{remotessh.ForwardDynamic, forwardDynamic},
+	}
+	for _, spec := range specs {
+		for _, value := range spec.values {
+			container := ""
+			if spec.forwardType == remotessh.ForwardLocal {
+				container = forwardContainer
+			}
+			forward, err := remotessh.ParseForward(spec.forwardType, value, container)
+			if err != nil {
+				return nil, err
+			}
+			forwards = append(forwards, forward)
+		}
+	}
+
+	if len(forwards) > 0 || len(server.Forwards) == 0 {
+		return forwards, nil
+	}
+
+	// 지정된 포워딩이 없으면 프리셋 선택
+	options := make([]huh.Option[int], len(server.Forwards))
+	for i, forward := range server.Forwards {
+		options[i] = huh.NewOption(fmt.Sprintf("%-16s %s", forward.Name, describePreset(forward)), i)
+	}
+
+	var selected []int
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[int]().
+				Title(fmt.Sprintf("🔀 Select forwards on %s", server.ServerName)).
+				Options(options...).
+				Value(&selected),
+		),
+	)
+	if err := form.Run(); err != nil {
+		return nil, err
+	}
+
+	for _, index := range selected {
+		forwards = append(forwards, server.Forwards[index])
+	}
+	return forwards, nil
+}
+
+func findForwardPreset(server models.Server, name string) (models.Forward, bool) {
+	for _, forward := range server.Forwards {
+		if forward.Name == name {
+			return forward, true
+		}
+	}
+	return models.Forward{}, false
+}
+
+func presetHint(server models.Server) string {
+	if len(server.Forwards) == 0 {
+		return ""
+	}
+	names := make([]string, len(server.Forwards))
+	for i, forward := range server.Forwards {
+		names[i] = forward.Name
+	}
+	return " (available: " + strings.Join(names, ", ") + ")"
+}
+
+// describePreset formats a preset without resolving its container.
+func describePreset(forward models.Forward) string {
+	if forward.Container != "" {
+		return fmt.Sprintf("%s → %s:%s (container)", forward.Local, forward.Container, forward.Remote)
+	}
+	return remotessh.DescribeForward(forward)
+}
+
+func init() {
+	forwardCmd.Flags().StringArrayVarP(&forwardLocal, "local", "L", nil, "Local forward [bind:]port:host:hostport")
+	forwardCmd.Flags().StringArrayVarP(&forwardRemote, "remote", "R", nil, "Remote forward [bind:]port:host:hostport")
+	forwardCmd.Flags().StringArrayVarP(&forwardDynamic, "dynamic", "D", nil, "SOCKS5 proxy on [bind:]port")
+	forwardCmd.Flags().StringVarP(&forwardContainer, "container", "c", "", "Container whose IP -L forwards target ([bind:]port:containerport)")
+	addSelectorFlags(forwardCmd)
+	rootCmd.AddCommand(forwardCmd)
+}
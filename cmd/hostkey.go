@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"remotelink/config"
+	remotessh "remotelink/ssh"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+var hostkeyCmd = &cobra.Command{
+	Use:   "hostkey",
+	Short: "Manage trusted host keys",
+}
+
+var hostkeyListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List trusted host keys",
+	Aliases: []string{"ls"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := remotessh.ListKnownHosts()
+		if err != nil {
+			return err
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("No trusted host keys. Keys are added on first connect or with 'remotelink hostkey pin'.")
+			return nil
+		}
+
+		fmt.Println(titleStyle.Render("Trusted host keys"))
+		for _, entry := range entries {
+			fmt.Printf("%s  %s\n   %s  %s\n",
+				labelStyle.Render(serverNameForHosts(entry.Hosts)),
+				valueStyle.Render(strings.Join(entry.Hosts, ",")),
+				containerImageStyle.Render(entry.KeyType),
+				valueStyle.Render(entry.Fingerprint),
+			)
+		}
+		fmt.Printf("\n%s\n", containerImageStyle.Render(remotessh.KnownHostsPath()))
+		return nil
+	},
+}
+
+var hostkeyForgetCmd = &cobra.Command{
+	Use:   "forget <server>",
+	Short: "Remove the trusted host key of a server",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		server, found := config.FindServer(args[0])
+		if !found {
+			return fmt.Errorf("server '%s' not found", args[0])
+		}
+
+		removed, err := remotessh.ForgetHostKey(server)
+		if err != nil {
+			return err
+		}
+
+		if removed == 0 {
+			fmt.Printf("No trusted host key for '%s'\n", server.ServerName)
+			return nil
+		}
+		fmt.Printf("✅ Forgot %d host key(s) for '%s'\n", removed, server.ServerName)
+		return nil
+	},
+}
+
+var hostkeyPinCmd = &cobra.Command{
+	Use:   "pin <server>",
+	Short: "Fetch and trust the current host key of a server",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		server, found := config.FindServer(args[0])
+		if !found {
+			return fmt.Errorf("server '%s' not found", args[0])
+		}
+
+		fingerprint, err := remotessh.PinHostKey(server)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Pinned host key for '%s'\n   %s\n", server.ServerName, fingerprint)
+		return nil
+	},
+}
+
+// confirmHostKey asks whether to trust a host key seen for the first time.
+func confirmHostKey(host, fingerprint string) (bool, error) {
+	var trust bool
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(fmt.Sprintf("🔑 Trust host key for %s?", host)).
+				Description(fmt.Sprintf("The authenticity of this host can't be established.\n%s", fingerprint)).
+				Affirmative("Trust").
+				Negative("Reject").
+				Value(&trust),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		return false, err
+	}
+	return trust, nil
+}
+
+// serverNameForHosts returns the configured server names whose address appears in a known_hosts entry.
+func serverNameForHosts(hosts []string) string {
+	var names []string
+	for _, server := range config.Servers {
+		address := remotessh.KnownHostAddress(server)
+		for _, host := range hosts {
+			if host == address {
+				names = append(names, server.ServerName)
+				break
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, ",")
+}
+
+func init() {
+	remotessh.ConfirmHostKey = confirmHostKey
+
+	hostkeyCmd.AddCommand(hostkeyListCmd, hostkeyForgetCmd, hostkeyPinCmd)
+	rootCmd.AddCommand(hostkeyCmd)
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"remotelink/config"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+const (
+	conflictSkip  = "skip"
+	conflictMerge = "merge"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import servers from other tools",
+}
+
+var importSSHConfigCmd = &cobra.Command{
+	Use:   "ssh-config [path]",
+	Short: "Import servers from an OpenSSH config file (default ~/.ssh/config)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath := config.DefaultSSHConfigPath()
+		if len(args) > 0 {
+			configPath = args[0]
+		}
+
+		hosts, err := config.ParseSSHConfig(configPath)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", configPath, err)
+		}
+		if len(hosts) == 0 {
+			fmt.Printf("No hosts found in %s\n", configPath)
+			return nil
+		}
+
+		// 가져올 호스트 선택 (새 호스트는 기본 선택)
+		options := make([]huh.Option[int], len(hosts))
+		for i, host := range hosts {
+			label := fmt.Sprintf("%-20s %s@%s:%d", host.Alias, host.User, host.HostName, host.Port)
+			_, exists := config.FindServer(host.Alias)
+			if exists {
+				label += " (exists)"
+			}
+			options[i] = huh.NewOption(label, i).Selected(!exists)
+		}
+
+		var selected []int
+		form := huh.NewForm(
+			huh.NewGroup(
+				huh.NewMultiSelect[int]().
+					Title(fmt.Sprintf("Import from %s", configPath)).
+					Description("Space to toggle, enter to confirm").
+					Options(options...).
+					Value(&selected),
+			),
+		)
+		if err := form.Run(); err != nil {
+			return err
+		}
+		if len(selected) == 0 {
+			fmt.Println("Nothing selected")
+			return nil
+		}
+
+		// 이미 있는 서버가 선택되었으면 처리 방식 확인
+		conflict := conflictSkip
+		for _, i := range selected {
+			if _, exists := config.FindServer(hosts[i].Alias); exists {
+				form := huh.NewForm(
+					huh.NewGroup(
+						huh.NewSelect[string]().
+							Title("Some servers already exist").
+							Options(
+								huh.NewOption("Skip existing servers", conflictSkip),
+								huh.NewOption("Merge connection settings into existing servers", conflictMerge),
+							).
+							Value(&conflict),
+					),
+				)
+				if err := form.Run(); err != nil {
+					return err
+				}
+				break
+			}
+		}
+
+		imported, merged, skipped := 0, 0, 0
+		var applied []config.SSHHost
+		for _, i := range selected {
+			host := hosts[i]
+
+			server := host.Server()
+			index := serverIndex(host.Alias)
+			switch {
+			case index < 0:
+				config.Servers = append(config.Servers, server)
+				applied = append(applied, host)
+				imported++
+			case conflict == conflictMerge:
+				existing := &config.Servers[index]
+				existing.HostIp = server.HostIp
+				existing.Port = server.Port
+				existing.Username = server.Username
+				if server.KeyPath != "" {
+					existing.KeyPath = server.KeyPath
+				}
+				applied = append(applied, host)
+				merged++
+			default:
+				skipped++
+			}
+		}
+
+		linkProxyJumps(applied)
+
+		// 점프 체인 검증 후 저장
+		for _, server := range config.Servers {
+			if _, err := config.JumpChain(server); err != nil {
+				return fmt.Errorf("import aborted: %w", err)
+			}
+		}
+		if err := config.SaveServers(); err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Imported %d, merged %d, skipped %d server(s)\n", imported, merged, skipped)
+		return nil
+	},
+}
+
+// linkProxyJumps maps ProxyJump chains onto the jump field. The hop right in front of a host becomes
+// its jump; earlier hops are linked to each other when they have no jump of their own.
+func linkProxyJumps(hosts []config.SSHHost) {
+	for _, host := range hosts {
+		hops := host.JumpHosts()
+		if len(hops) == 0 {
+			continue
+		}
+
+		chain := append(hops, host.Alias)
+		for i := len(chain) - 1; i > 0; i-- {
+			index := serverIndex(chain[i])
+			if index < 0 {
+				break
+			}
+			if _, found := config.FindServer(chain[i-1]); !found {
+				fmt.Printf("⚠️  '%s': jump host '%s' is not a configured server, skipped\n", chain[i], chain[i-1])
+				break
+			}
+
+			server := &config.Servers[index]
+			if server.Jump != "" && i < len(chain)-1 {
+				break
+			}
+			server.Jump = chain[i-1]
+		}
+	}
+}
+
+// serverIndex returns the position of the named server in config.Servers, or -1.
+func serverIndex(name string) int {
+	for i, server := range config.Servers {
+		if server.ServerName == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func init() {
+	importCmd.AddCommand(importSSHConfigCmd)
+	rootCmd.AddCommand(importCmd)
+}
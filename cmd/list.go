@@ -2,14 +2,17 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"remotelink/config"
 	"remotelink/models"
 	remotessh "remotelink/ssh"
+	"slices"
+	"strings"
 
-	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/huh/spinner"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var (
@@ -45,46 +48,59 @@ var (
 
 	errorStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FF4444"))
+
+	groupStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#7D56F4"))
+
+	tagStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#04B575"))
 )
 
+var listAll bool
+
 var listCmd = &cobra.Command{
-	Use:   "ls",
+	Use:   "ls [server-name]",
 	Short: "List servers",
+	Long: `List servers as a tree grouped by group, then pick one to view its details.
+
+With a server name, its details are shown directly. The details include each container's
+health, uptime, published ports and restart count; --all adds stopped containers.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(config.Servers) == 0 {
 			fmt.Println("No servers configured. Use 'remotelink add' to add a server.")
 			return nil
 		}
 
-		// 서버 선택 옵션 생성
-		options := make([]huh.Option[int], len(config.Servers))
-		for i, server := range config.Servers {
-			label := fmt.Sprintf("%-20s %s@%s:%d",
-				server.ServerName,
-				server.Username,
-				server.HostIp,
-				server.Port)
+		var server models.Server
+		if len(args) > 0 {
+			var err error
+			if server, err = findServer(args[0]); err != nil {
+				return err
+			}
+		} else {
+			servers, err := candidateServers()
+			if err != nil {
+				return err
+			}
+			printServerTree(servers)
 
-			options[i] = huh.NewOption(label, i)
-		}
+			// 파이프로 출력할 때는 트리만 표시
+			if !term.IsTerminal(int(os.Stdin.Fd())) {
+				return nil
+			}
 
-		var selectedIndex int
-		form := huh.NewForm(
-			huh.NewGroup(
-				huh.NewSelect[int]().
-					Title("Server List").
-					Description("Select a server to view details").
-					Options(options...).
-					Value(&selectedIndex),
-			),
-		)
-
-		if err := form.Run(); err != nil {
-			return nil
+			fmt.Println()
+			if server, err = SelectServer(); err != nil {
+				return nil
+			}
 		}
 
 		// 선택된 서버의 컨테이너를 실시간 조회
-		server := config.Servers[selectedIndex]
+		if err := remotessh.TrustHostKey(server); err != nil {
+			return err
+		}
 
 		var containers []models.Container
 		var fetchErr error
@@ -92,13 +108,20 @@ var listCmd = &cobra.Command{
 		err := spinner.New().
 			Title(fmt.Sprintf("Fetching containers from %s...", server.ServerName)).
 			Action(func() {
-				containers, fetchErr = remotessh.FetchContainers(server)
+				containers, fetchErr = remotessh.FetchTargets(server, listAll)
 			}).
 			Run()
 
 		if err != nil {
 			return err
 		}
+		if fetchErr == nil {
+			// 기록은 접속 후보이므로 실행 중인 컨테이너만
+			running := slices.DeleteFunc(slices.Clone(containers), func(c models.Container) bool {
+				return c.State != "" && c.State != remotessh.StateRunning
+			})
+			config.RecordContainers(server.ServerName, running)
+		}
 
 		printServerDetail(server, containers, fetchErr)
 
@@ -106,6 +129,45 @@ var listCmd = &cobra.Command{
 	},
 }
 
+// printServerTree prints servers grouped by group, ungrouped servers last.
+func printServerTree(servers []models.Server) {
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Servers (%d)", len(servers))))
+
+	groups, byGroup := config.GroupServers(servers)
+	width := 0
+	for _, server := range servers {
+		width = max(width, len(server.ServerName))
+	}
+
+	for i, group := range groups {
+		name := group
+		if name == "" {
+			name = "(ungrouped)"
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(groupStyle.Render(fmt.Sprintf("%s (%d)", name, len(byGroup[group]))))
+
+		members := byGroup[group]
+		for j, server := range members {
+			prefix := "├─"
+			if j == len(members)-1 {
+				prefix = "└─"
+			}
+			line := fmt.Sprintf("  %s %s  %s",
+				prefix,
+				containerNameStyle.Render(fmt.Sprintf("%-*s", width, server.ServerName)),
+				containerImageStyle.Render(fmt.Sprintf("%s@%s:%d", server.Username, server.HostIp, server.Port)),
+			)
+			if len(server.Tags) > 0 {
+				line += "  " + tagStyle.Render("#"+strings.Join(server.Tags, " #"))
+			}
+			fmt.Println(line)
+		}
+	}
+}
+
 func printServerDetail(server models.Server, containers []models.Container, fetchErr error) {
 	var info string
 
@@ -119,30 +181,157 @@ func printServerDetail(server models.Server, containers []models.Container, fetc
 	if server.DefaultPath != "" {
 		info += labelStyle.Render("Default Path") + "  " + valueStyle.Render(server.DefaultPath) + "\n"
 	}
+	if server.Jump != "" {
+		info += labelStyle.Render("Jump Host") + "  " + valueStyle.Render(server.Jump) + "\n"
+	}
+	if server.Transfer != "" {
+		info += labelStyle.Render("Transfer") + "  " + valueStyle.Render(server.Transfer) + "\n"
+	}
+	if server.ContainerRuntime != "" {
+		info += labelStyle.Render("Runtime") + "  " + valueStyle.Render(server.ContainerRuntime) + "\n"
+	}
+	if server.Kubeconfig != "" {
+		info += labelStyle.Render("Kubeconfig") + "  " + valueStyle.Render(server.Kubeconfig) + "\n"
+	}
+	if server.Group != "" {
+		info += labelStyle.Render("Group") + "  " + valueStyle.Render(server.Group) + "\n"
+	}
+	if len(server.Tags) > 0 {
+		info += labelStyle.Render("Tags") + "  " + valueStyle.Render(strings.Join(server.Tags, ", ")) + "\n"
+	}
+	for i, forward := range server.Forwards {
+		label := ""
+		if i == 0 {
+			label = "Forwards"
+		}
+		info += labelStyle.Render(label) + "  " + valueStyle.Render(forward.Name+": "+describePreset(forward)) + "\n"
+	}
 
 	// 컨테이너 정보
+	runtimeContainers, pods := remotessh.SplitPods(containers)
 	if fetchErr != nil {
 		info += "\n" + errorStyle.Render("Failed to fetch containers: "+fetchErr.Error())
-	} else if len(containers) > 0 {
-		info += "\n" + containerHeaderStyle.Render(fmt.Sprintf("Containers (%d)", len(containers))) + "\n"
-		for i, c := range containers {
-			prefix := "├─"
-			if i == len(containers)-1 {
-				prefix = "└─"
+	} else if len(runtimeContainers) > 0 {
+		header := fmt.Sprintf("Containers (%d)", len(runtimeContainers))
+		if listAll {
+			running := 0
+			for _, c := range runtimeContainers {
+				if c.State == remotessh.StateRunning {
+					running++
+				}
+			}
+			header = fmt.Sprintf("Containers (%d, %d running)", len(runtimeContainers), running)
+		}
+		info += "\n" + containerHeaderStyle.Render(header) + "\n"
+
+		// compose 프로젝트별로 묶고, 프로젝트가 없으면 기존처럼 평평한 목록
+		projects, byProject := remotessh.GroupContainers(runtimeContainers)
+		for _, project := range projects {
+			members := byProject[project]
+			indent := "  "
+			if len(projects) > 1 || project != "" {
+				name := project
+				if name == "" {
+					name = "(standalone)"
+				}
+				info += "  " + groupStyle.Render(name) + "\n"
+				indent = "    "
+			}
+
+			width := 0
+			for _, c := range members {
+				width = max(width, len(c.Service))
+			}
+			for i, c := range members {
+				prefix := "├─"
+				if i == len(members)-1 {
+					prefix = "└─"
+				}
+				name := containerNameStyle.Render(c.ContainerName)
+				detail := "(" + c.ImageName + ")"
+				if c.Service != "" {
+					name = containerNameStyle.Render(fmt.Sprintf("%-*s", width, c.Service))
+					detail = c.ContainerName + " " + detail
+				}
+				info += fmt.Sprintf("%s%s %s  %s\n", indent, prefix, name, containerImageStyle.Render(detail))
+				if summary := containerSummary(c); summary != "" {
+					branch := "│  "
+					if i == len(members)-1 {
+						branch = "   "
+					}
+					info += indent + branch + summary + "\n"
+				}
 			}
-			info += fmt.Sprintf("  %s %s  %s\n",
-				prefix,
-				containerNameStyle.Render(c.ContainerName),
-				containerImageStyle.Render("("+c.ImageName+")"),
-			)
 		}
-	} else {
+	} else if len(pods) == 0 && listAll {
+		info += "\n" + valueStyle.Render("No containers")
+	} else if len(pods) == 0 {
 		info += "\n" + valueStyle.Render("No running containers")
 	}
 
+	// 쿠버네티스 파드는 네임스페이스별로 표시
+	if len(pods) > 0 {
+		info += "\n" + containerHeaderStyle.Render(fmt.Sprintf("Pods (%d containers)", len(pods))) + "\n"
+		namespaces, byNamespace := remotessh.GroupPods(pods)
+		for _, namespace := range namespaces {
+			info += "  " + groupStyle.Render(namespace) + "\n"
+			members := byNamespace[namespace]
+			for i, c := range members {
+				prefix := "├─"
+				if i == len(members)-1 {
+					prefix = "└─"
+				}
+				info += fmt.Sprintf("    %s %s  %s\n", prefix,
+					containerNameStyle.Render(c.Pod+"/"+c.ContainerName),
+					containerImageStyle.Render("("+c.ImageName+")"))
+			}
+		}
+	}
+
 	fmt.Println(serverInfoStyle.Render(info))
 }
 
+// containerSummary is the detail line under a container: health or state, uptime, published
+// ports, restarts and ID.
+func containerSummary(c models.Container) string {
+	var parts []string
+	switch {
+	case c.Health != "" && c.State == remotessh.StateRunning:
+		parts = append(parts, containerHealthStyle(c.Health).Render("● "+c.Health))
+	case c.State != "":
+		parts = append(parts, containerStateStyle(c.State).Render("● "+c.State))
+	}
+
+	if c.Uptime != "" {
+		parts = append(parts, containerImageStyle.Render("up "+c.Uptime))
+	} else if c.Status != "" {
+		parts = append(parts, containerImageStyle.Render(c.Status))
+	}
+	if len(c.Ports) > 0 {
+		parts = append(parts, valueStyle.Render(strings.Join(c.Ports, ", ")))
+	}
+	if c.RestartCount > 0 {
+		parts = append(parts, warnStyle.Render(fmt.Sprintf("↻ %d restarts", c.RestartCount)))
+	}
+	if c.ID != "" {
+		parts = append(parts, containerImageStyle.Render(c.ID[:min(len(c.ID), 12)]))
+	}
+	return strings.Join(parts, containerImageStyle.Render(" · "))
+}
+
+// containerHealthStyle colors a health check result.
+func containerHealthStyle(health string) lipgloss.Style {
+	switch health {
+	case "healthy":
+		return okStyle
+	case "unhealthy":
+		return failedStyle
+	}
+	return warnStyle
+}
+
 func init() {
+	listCmd.Flags().BoolVarP(&listAll, "all", "a", false, "Include stopped containers in the details")
+	addSelectorFlags(listCmd)
 	rootCmd.AddCommand(listCmd)
 }
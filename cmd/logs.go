@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"remotelink/config"
+	"remotelink/models"
+	remotessh "remotelink/ssh"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsContainers []string
+	logsFile       string
+	logsUnit       string
+	logsSince      string
+	logsTail       string
+	logsFollow     bool
+	logsGrep       string
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs [server[/container]...] [container...]",
+	Short: "Stream container, file or journald logs from one or more servers",
+	Long: `Stream logs from containers, host log files or systemd units over SSH.
+
+Each argument is a server, a server/container pair, or a container on the server named before it.
+--group and --tag add servers, and --container applies to every server. Servers without a
+container read --file or --unit instead; with neither, a picker lists their running containers.
+
+With more than one source the lines are interleaved and prefixed with a colored source name.
+--grep filters lines with a regular expression. Ctrl+C stops the remote processes too.
+
+  remotelink logs prod web -f --tail 100
+  remotelink logs prod/web staging/web --since 10m --grep error
+  remotelink logs --group web --unit nginx -f`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(config.Servers) == 0 {
+			fmt.Println("❌ No servers configured")
+			return nil
+		}
+		if logsFile != "" && logsUnit != "" {
+			return fmt.Errorf("--file and --unit cannot be combined")
+		}
+		if err := remotessh.ValidateTail(logsTail); err != nil {
+			return err
+		}
+
+		var filter *regexp.Regexp
+		if logsGrep != "" {
+			var err error
+			if filter, err = regexp.Compile(logsGrep); err != nil {
+				return fmt.Errorf("invalid --grep: %w", err)
+			}
+		}
+
+		sources, err := logSources(args)
+		if err != nil {
+			return err
+		}
+		if len(sources) == 0 {
+			fmt.Println("No logs selected")
+			return nil
+		}
+
+		return streamLogSources(sources, filter)
+	},
+}
+
+// logSource is one log stream on one server.
+type logSource struct {
+	server models.Server
+	source remotessh.LogSource
+}
+
+// name is the source's output prefix, e.g. "prod/web" or "prod:nginx".
+func (s logSource) name() string {
+	switch {
+	case s.source.Container != "":
+		return s.server.ServerName + "/" + s.source.Container
+	case s.source.Unit != "":
+		return s.server.ServerName + ":" + s.source.Unit
+	}
+	return s.server.ServerName + ":" + s.source.File
+}
+
+// logSources resolves the arguments and flags into the streams to follow, asking for
+// containers on servers that name none.
+func logSources(args []string) ([]logSource, error) {
+	servers, containers, err := resolveContainerArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []logSource
+	for _, server := range servers {
+		if err := remotessh.TrustHostKey(server); err != nil {
+			return nil, err
+		}
+
+		names := slices.Concat(containers[server.ServerName], logsContainers)
+		switch {
+		case len(names) > 0:
+		case logsFile != "":
+			sources = append(sources, logSource{server: server, source: remotessh.LogSource{File: logsFile}})
+			continue
+		case logsUnit != "":
+			sources = append(sources, logSource{server: server, source: remotessh.LogSource{Unit: logsUnit}})
+			continue
+		default:
+			picked, err := pickLogContainers(server)
+			if err != nil {
+				return nil, err
+			}
+			names = picked
+		}
+
+		for _, name := range names {
+			sources = append(sources, logSource{server: server, source: remotessh.LogSource{Container: name}})
+		}
+	}
+	return sources, nil
+}
+
+// pickLogContainers asks which running containers or pod containers on the server to read logs from.
+func pickLogContainers(server models.Server) ([]string, error) {
+	var containers []models.Container
+	var fetchErr error
+	err := spinner.New().
+		Title(fmt.Sprintf("Fetching containers from %s...", server.ServerName)).
+		Action(func() {
+			containers, fetchErr = remotessh.FetchTargets(server, false)
+		}).
+		Run()
+	if err != nil {
+		return nil, err
+	}
+	if fetchErr != nil {
+		return nil, fmt.Errorf("❌ %w; use --file or --unit for host logs", fetchErr)
+	}
+	config.RecordContainers(server.ServerName, containers)
+
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("no running containers on %s; use --file or --unit for host logs", server.ServerName)
+	}
+
+	var names []string
+	err = huh.NewMultiSelect[string]().
+		Title(fmt.Sprintf("Select containers on %s", server.ServerName)).
+		Options(targetOptions(containers)...).
+		Filterable(true).
+		Value(&names).
+		Run()
+	return names, err
+}
+
+// streamLogSources follows every source at once until they end or Ctrl+C, prefixing the lines
+// with the source name when there is more than one.
+func streamLogSources(sources []logSource, filter *regexp.Regexp) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	width := 0
+	for _, s := range sources {
+		width = max(width, len(s.name()))
+	}
+
+	opts := remotessh.LogOptions{Since: logsSince, Tail: logsTail, Follow: logsFollow}
+	var failed atomic.Int32
+	var outMu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i, s := range sources {
+		prefix := ""
+		if len(sources) > 1 {
+			style := lipgloss.NewStyle().Bold(true).Foreground(prefixPalette[i%len(prefixPalette)])
+			prefix = style.Render(fmt.Sprintf("%-*s |", width, s.name()))
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stdout := &linePrefixer{mu: &outMu, out: os.Stdout, prefix: prefix, filter: filter}
+			stderr := &linePrefixer{mu: &outMu, out: os.Stderr, prefix: prefix, filter: filter}
+			err := remotessh.StreamLogs(ctx, s.server, s.source, opts, stdout, stderr)
+			stdout.Flush()
+			stderr.Flush()
+
+			// 다른 스트림은 계속 진행되므로 실패는 바로 표시
+			if err != nil && ctx.Err() == nil {
+				failed.Add(1)
+				outMu.Lock()
+				fmt.Fprintln(os.Stderr, failedStyle.Render(fmt.Sprintf("✗ %s: %s", s.name(), firstLine(err.Error()))))
+				outMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := failed.Load(); n > 0 {
+		return fmt.Errorf("%d of %d log streams failed", n, len(sources))
+	}
+	return nil
+}
+
+func init() {
+	logsCmd.Flags().StringSliceVarP(&logsContainers, "container", "c", nil, "Container to read on every server (repeatable)")
+	logsCmd.Flags().StringVar(&logsFile, "file", "", "Log file on the host to read instead of a container")
+	logsCmd.Flags().StringVarP(&logsUnit, "unit", "u", "", "systemd unit to read from journald instead of a container")
+	logsCmd.Flags().StringVar(&logsSince, "since", "", "Only show logs since a duration (e.g. 10m) or timestamp")
+	logsCmd.Flags().StringVarP(&logsTail, "tail", "n", "all", "Number of lines to show from the end, or \"all\"")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Keep streaming new lines until interrupted")
+	logsCmd.Flags().StringVar(&logsGrep, "grep", "", "Only show lines matching a regular expression")
+	addSelectorFlags(logsCmd)
+	rootCmd.AddCommand(logsCmd)
+}
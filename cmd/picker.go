@@ -0,0 +1,361 @@
+package cmd
+
+import (
+	"fmt"
+	"remotelink/config"
+	"remotelink/models"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+)
+
+// pickerHeight is the number of rows shown at once; longer lists scroll.
+const pickerHeight = 12
+
+var (
+	pickerCursorStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4"))
+	pickerMatchStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#F25D94"))
+	pickerDimStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262"))
+)
+
+// pickResult is what the user chose in the server picker.
+// Target is true when the host or a container was chosen directly with ctrl+o or tab,
+// in which case Container is the container name, or "" for the host.
+type pickResult struct {
+	Server    models.Server
+	Container string
+	Target    bool
+}
+
+// pickerItem is one selectable row. text is both what is displayed and what the query is matched against.
+type pickerItem struct {
+	server    models.Server
+	container string
+	text      string
+	lastUsed  time.Time
+}
+
+type pickerItems []pickerItem
+
+func (items pickerItems) String(i int) string { return items[i].text }
+func (items pickerItems) Len() int            { return len(items) }
+
+// pickerRow is a visible row: an item plus the byte offsets of the characters the query matched.
+type pickerRow struct {
+	item    pickerItem
+	matched []int
+}
+
+type pickerModel struct {
+	title        string
+	allowTargets bool
+	input        textinput.Model
+
+	servers pickerItems
+	// 컨테이너 목록을 보고 있을 때만 설정
+	containers pickerItems
+	parent     *pickerItem
+
+	rows   []pickerRow
+	cursor int
+	offset int
+
+	result    *pickResult
+	cancelled bool
+}
+
+func newPickerModel(title string, servers []models.Server, allowTargets bool) pickerModel {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "type to search name, host, user, #tag or container"
+	input.Focus()
+
+	m := pickerModel{title: title, allowTargets: allowTargets, input: input}
+
+	nameWidth, groupWidth := 0, 0
+	for _, server := range servers {
+		nameWidth = max(nameWidth, len(server.ServerName))
+		groupWidth = max(groupWidth, len(server.Group))
+	}
+
+	// 기본 순서: 그룹 순서대로, 최근 사용한 서버를 맨 위로
+	groups, byGroup := config.GroupServers(servers)
+	for _, group := range groups {
+		for _, server := range byGroup[group] {
+			text := fmt.Sprintf("%-*s  ", nameWidth, server.ServerName)
+			if groupWidth > 0 {
+				text += fmt.Sprintf("%-*s  ", groupWidth, server.Group)
+			}
+			text += fmt.Sprintf("%s@%s:%d", server.Username, server.HostIp, server.Port)
+			if len(server.Tags) > 0 {
+				text += "  #" + strings.Join(server.Tags, " #")
+			}
+			if containers := config.KnownContainers(server); len(containers) > 0 {
+				text += "  🐳 " + strings.Join(containers, ", ")
+			}
+
+			m.servers = append(m.servers, pickerItem{
+				server:   server,
+				text:     text,
+				lastUsed: config.History(server.ServerName).LastUsed,
+			})
+		}
+	}
+	slices.SortStableFunc(m.servers, func(a, b pickerItem) int {
+		return b.lastUsed.Compare(a.lastUsed)
+	})
+
+	m.filter()
+	return m
+}
+
+// items returns the list currently being searched: servers, or the containers of one server.
+func (m pickerModel) items() pickerItems {
+	if m.parent != nil {
+		return m.containers
+	}
+	return m.servers
+}
+
+// filter rebuilds the visible rows from the query. Matches are ordered by score, then by recency.
+func (m *pickerModel) filter() {
+	items := m.items()
+	query := strings.TrimSpace(m.input.Value())
+
+	m.rows = m.rows[:0]
+	if query == "" {
+		for _, item := range items {
+			m.rows = append(m.rows, pickerRow{item: item})
+		}
+	} else {
+		matches := fuzzy.FindFromNoSort(query, items)
+		slices.SortStableFunc(matches, func(a, b fuzzy.Match) int {
+			if a.Score != b.Score {
+				return b.Score - a.Score
+			}
+			return items[b.Index].lastUsed.Compare(items[a.Index].lastUsed)
+		})
+		for _, match := range matches {
+			m.rows = append(m.rows, pickerRow{item: items[match.Index], matched: match.MatchedIndexes})
+		}
+	}
+
+	m.cursor, m.offset = 0, 0
+}
+
+// openContainers switches the list to the host and known containers of the highlighted server.
+func (m *pickerModel) openContainers() {
+	if len(m.rows) == 0 {
+		return
+	}
+	parent := m.rows[m.cursor].item
+	m.parent = &parent
+
+	m.containers = pickerItems{{server: parent.server, text: fmt.Sprintf("🖥️  %s (Host)", parent.server.ServerName)}}
+	for _, name := range config.KnownContainers(parent.server) {
+		icon := "🐳 "
+		if strings.HasPrefix(name, models.PodRefPrefix) {
+			icon = "☸️  "
+		}
+		m.containers = append(m.containers, pickerItem{server: parent.server, container: name, text: icon + name})
+	}
+
+	m.input.SetValue("")
+	m.filter()
+}
+
+func (m *pickerModel) closeContainers() {
+	m.parent = nil
+	m.input.SetValue("")
+	m.filter()
+}
+
+func (m *pickerModel) move(delta int) {
+	if len(m.rows) == 0 {
+		return
+	}
+	m.cursor = min(max(m.cursor+delta, 0), len(m.rows)-1)
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+pickerHeight {
+		m.offset = m.cursor - pickerHeight + 1
+	}
+}
+
+func (m pickerModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "ctrl+c":
+			m.cancelled = true
+			return m, tea.Quit
+		case "esc":
+			switch {
+			case m.input.Value() != "":
+				m.input.SetValue("")
+				m.filter()
+			case m.parent != nil:
+				m.closeContainers()
+			default:
+				m.cancelled = true
+				return m, tea.Quit
+			}
+			return m, nil
+		case "up", "ctrl+p", "ctrl+k":
+			m.move(-1)
+			return m, nil
+		case "down", "ctrl+n", "ctrl+j":
+			m.move(1)
+			return m, nil
+		case "pgup":
+			m.move(-pickerHeight)
+			return m, nil
+		case "pgdown":
+			m.move(pickerHeight)
+			return m, nil
+		case "enter":
+			if len(m.rows) == 0 {
+				return m, nil
+			}
+			item := m.rows[m.cursor].item
+			m.result = &pickResult{Server: item.server, Container: item.container, Target: m.parent != nil}
+			return m, tea.Quit
+		case "ctrl+o":
+			if !m.allowTargets || m.parent != nil || len(m.rows) == 0 {
+				return m, nil
+			}
+			m.result = &pickResult{Server: m.rows[m.cursor].item.server, Target: true}
+			return m, tea.Quit
+		case "tab":
+			if m.allowTargets && m.parent == nil {
+				m.openContainers()
+			}
+			return m, nil
+		case "shift+tab":
+			if m.parent != nil {
+				m.closeContainers()
+			}
+			return m, nil
+		}
+	}
+
+	before := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != before {
+		m.filter()
+	}
+	return m, cmd
+}
+
+func (m pickerModel) View() string {
+	// 선택이 끝나면 화면에서 지움
+	if m.result != nil || m.cancelled {
+		return ""
+	}
+
+	var b strings.Builder
+	title := m.title
+	if m.parent != nil {
+		title = fmt.Sprintf("📍 Select target on %s", m.parent.server.ServerName)
+	}
+	b.WriteString(pickerCursorStyle.Render(title) + "\n")
+	b.WriteString(m.input.View() + "\n\n")
+
+	if len(m.rows) == 0 {
+		b.WriteString(pickerDimStyle.Render("  no matches") + "\n")
+	}
+
+	end := min(m.offset+pickerHeight, len(m.rows))
+	for i := m.offset; i < end; i++ {
+		row := m.rows[i]
+		cursor := "  "
+		if i == m.cursor {
+			cursor = pickerCursorStyle.Render("▸ ")
+		}
+		line := cursor + highlightMatches(row.item.text, row.matched, i == m.cursor)
+		if !row.item.lastUsed.IsZero() {
+			line += "  " + pickerDimStyle.Render(timeAgo(row.item.lastUsed))
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if len(m.rows) > pickerHeight {
+		b.WriteString(pickerDimStyle.Render(fmt.Sprintf("  %d/%d", m.cursor+1, len(m.rows))) + "\n")
+	}
+
+	help := "↑/↓ move • enter select • esc quit"
+	if m.allowTargets {
+		if m.parent != nil {
+			help = "↑/↓ move • enter connect • shift+tab back • esc back"
+		} else {
+			help = "↑/↓ move • enter select • ctrl+o host • tab containers • esc quit"
+		}
+	}
+	b.WriteString("\n" + pickerDimStyle.Render(help) + "\n")
+
+	return b.String()
+}
+
+// highlightMatches renders text with the matched byte offsets emphasized.
+func highlightMatches(text string, matched []int, selected bool) string {
+	base := lipgloss.NewStyle()
+	if selected {
+		base = base.Bold(true)
+	}
+	if len(matched) == 0 {
+		return base.Render(text)
+	}
+
+	var b strings.Builder
+	next := 0
+	for i, r := range text {
+		if next < len(matched) && matched[next] == i {
+			b.WriteString(pickerMatchStyle.Render(string(r)))
+			next++
+			continue
+		}
+		b.WriteString(base.Render(string(r)))
+	}
+	return b.String()
+}
+
+// timeAgo formats how long ago t was, coarsely.
+func timeAgo(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// runPicker shows the fuzzy picker over servers. With allowTargets, the host or a
+// container can be chosen directly. Cancelling returns huh.ErrUserAborted.
+func runPicker(title string, servers []models.Server, allowTargets bool) (pickResult, error) {
+	final, err := tea.NewProgram(newPickerModel(title, servers, allowTargets)).Run()
+	if err != nil {
+		return pickResult{}, err
+	}
+
+	m := final.(pickerModel)
+	if m.result == nil {
+		return pickResult{}, huh.ErrUserAborted
+	}
+	return *m.result, nil
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"remotelink/models"
+	remotessh "remotelink/ssh"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pingWatch    bool
+	pingInterval time.Duration
+	pingParallel int
+)
+
+var pingDimStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262"))
+
+var pingCmd = &cobra.Command{
+	Use:   "ping [servers...]",
+	Short: "Check TCP, SSH and the container runtime on servers",
+	Long: `Check TCP reachability, SSH authentication and container runtime availability on servers.
+
+Without server names every server is checked, narrowed by --group/--tag.
+Exits non-zero when any check fails, so it can run from cron.
+A server without docker, podman or nerdctl installed is reported but does not count as a failure.
+
+  remotelink ping
+  remotelink ping --group web --watch`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		servers, err := pingTargets(args)
+		if err != nil {
+			return err
+		}
+		if len(servers) == 0 {
+			fmt.Println("No servers configured")
+			return nil
+		}
+
+		// 동시 실행 중에는 프롬프트를 띄울 수 없으므로 호스트 키를 먼저 확인
+		trustErrs := make([]error, len(servers))
+		for i, server := range servers {
+			trustErrs[i] = remotessh.TrustHostKey(server)
+		}
+
+		if !pingWatch {
+			results := pingServers(servers, trustErrs)
+			fmt.Println(renderPingTable(servers, results))
+			return pingFailures(results)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		var results []remotessh.Health
+		for {
+			results = pingServers(servers, trustErrs)
+
+			// 화면을 지우고 다시 그림
+			fmt.Print("\033[H\033[2J")
+			fmt.Println(renderPingTable(servers, results))
+			fmt.Println(pingDimStyle.Render(fmt.Sprintf("Last checked %s · every %s · Ctrl+C to stop",
+				time.Now().Format("15:04:05"), pingInterval)))
+
+			select {
+			case <-ctx.Done():
+				return pingFailures(results)
+			case <-time.After(pingInterval):
+			}
+		}
+	},
+}
+
+// pingTargets resolves named servers, or every server matching --group/--tag.
+func pingTargets(names []string) ([]models.Server, error) {
+	if len(names) == 0 {
+		return candidateServers()
+	}
+
+	servers := make([]models.Server, 0, len(names))
+	for _, name := range names {
+		server, err := findServer(name)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, server)
+	}
+	return servers, nil
+}
+
+// pingServers checks servers with at most --parallel at once. Servers whose host key could
+// not be trusted are reported as SSH failures without connecting.
+func pingServers(servers []models.Server, trustErrs []error) []remotessh.Health {
+	results := make([]remotessh.Health, len(servers))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < max(pingParallel, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if trustErrs[i] != nil {
+					results[i] = remotessh.Health{SSHErr: trustErrs[i]}
+					continue
+				}
+				results[i] = remotessh.CheckHealth(servers[i])
+			}
+		}()
+	}
+
+	for i := range servers {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+func pingFailures(results []remotessh.Health) error {
+	failed := 0
+	for _, result := range results {
+		if !result.OK() {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d servers failed", failed, len(results))
+	}
+	return nil
+}
+
+func renderPingTable(servers []models.Server, results []remotessh.Health) string {
+	rows := make([][]string, len(servers))
+	for i, server := range servers {
+		result := results[i]
+
+		tcp := okStyle.Render("✓ " + formatLatency(result.TCPLatency))
+		if result.TCPErr != nil {
+			tcp = failedStyle.Render("✗ " + shortError(result.TCPErr))
+		} else if result.TCPAddr == "" {
+			tcp = pingDimStyle.Render("-")
+		}
+
+		ssh := pingDimStyle.Render("-")
+		switch {
+		case result.SSHErr != nil:
+			ssh = failedStyle.Render("✗ " + shortError(result.SSHErr))
+		case result.SSHLatency > 0:
+			ssh = okStyle.Render("✓ " + formatLatency(result.SSHLatency))
+		}
+
+		docker := pingDimStyle.Render("-")
+		switch {
+		case !result.DockerChecked:
+		case result.DockerErr != nil:
+			docker = failedStyle.Render("✗ " + result.DockerErr.Error())
+		case !result.DockerInstalled:
+			docker = pingDimStyle.Render("not installed")
+		default:
+			docker = okStyle.Render(fmt.Sprintf("✓ %d running", result.DockerContainers))
+		}
+
+		status := okStyle.Render("ok")
+		if !result.OK() {
+			status = failedStyle.Render("failed")
+		}
+
+		host := fmt.Sprintf("%s@%s:%d", server.Username, server.HostIp, server.Port)
+		if server.Jump != "" {
+			host += " via " + server.Jump
+		}
+
+		rows[i] = []string{server.ServerName, host, tcp, ssh, docker, status}
+	}
+
+	return newTable(-1, []string{"SERVER", "HOST", "TCP", "SSH", "CONTAINERS", "STATUS"}, rows).
+		Render()
+}
+
+func formatLatency(d time.Duration) string {
+	if d < time.Millisecond {
+		return "<1ms"
+	}
+	return d.Round(time.Millisecond).String()
+}
+
+// shortError reduces a check error to a few words for the table.
+func shortError(err error) string {
+	switch {
+	case errors.Is(err, remotessh.ErrAuthFailed):
+		return "auth failed"
+	case errors.Is(err, remotessh.ErrHostKeyMismatch):
+		return "host key changed"
+	case errors.Is(err, remotessh.ErrHostKeyUnknown):
+		return "unknown host key"
+	}
+
+	// "dial tcp 10.0.0.1:22: connect: connection refused" → "connection refused"
+	line := firstLine(err.Error())
+	if i := strings.LastIndex(line, ": "); i >= 0 {
+		line = line[i+2:]
+	}
+	return line
+}
+
+func init() {
+	pingCmd.Flags().BoolVarP(&pingWatch, "watch", "w", false, "Refresh periodically until interrupted")
+	pingCmd.Flags().DurationVar(&pingInterval, "interval", 5*time.Second, "Refresh interval for --watch")
+	pingCmd.Flags().IntVarP(&pingParallel, "parallel", "p", 16, "Maximum number of servers to check at once")
+	addSelectorFlags(pingCmd)
+	rootCmd.AddCommand(pingCmd)
+}
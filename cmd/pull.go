@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"remotelink/config"
 	remotessh "remotelink/ssh"
@@ -9,62 +10,123 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	pullServer    string
+	pullContainer string
+)
+
 var pullCmd = &cobra.Command{
-	Use:     "pull [remote-path] [local-path]",
-	Short:   "Download file or directory from remote server via scp",
-	Aliases: []string{"download", "dl"},
+	Use:   "pull [[server[/container]:]remote-path] [local-path]",
+	Short: "Download file or directory from remote server via SFTP, scp or rsync",
+	Long: `Download file or directory from remote server via SFTP, scp or rsync.
+
+The source can name the server (and optionally a container on it) scp-style,
+or they can be given with --server and --container.
+Anything missing is asked for interactively.
+
+  remotelink pull prod:/var/log/app.log .
+  remotelink pull prod/web:/app/logs ./logs
+  remotelink pull --server prod /var/log/app.log .
+
+Progress, resume, verification and the rsync options work as for "remotelink send";
+.remotelinkignore is read from the local destination directory.
+
+  remotelink pull prod:/var/www/uploads ./uploads --transfer rsync --dry-run`,
+	Aliases:      []string{"download", "dl"},
+	SilenceUsage: true,
+	Args:         cobra.MaximumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(config.Servers) == 0 {
 			fmt.Println("❌ No servers configured")
 			return nil
 		}
 
-		// 서버 선택
-		server, err := SelectServer()
+		var remoteSpec, localPath string
+		if len(args) > 0 {
+			remoteSpec = args[0]
+		}
+		if len(args) > 1 {
+			localPath = args[1]
+		}
+
+		// 서버 결정: server:path 주소 → --server → 선택 화면
+		target, isRemote, err := parseRemoteTarget(remoteSpec)
 		if err != nil {
 			return err
 		}
-
-		// 경로 입력
-		var remotePath, localPath string
-
-		if len(args) >= 2 {
-			remotePath = args[0]
-			localPath = args[1]
+		if isRemote {
+			if pullServer != "" && pullServer != target.Server.ServerName {
+				return fmt.Errorf("--server '%s' conflicts with source '%s'", pullServer, remoteSpec)
+			}
+			switch {
+			case target.Container == "":
+				target.Container = pullContainer
+			case pullContainer != "" && pullContainer != target.Container:
+				return fmt.Errorf("--container '%s' conflicts with source '%s'", pullContainer, remoteSpec)
+			}
 		} else {
-			form := huh.NewForm(
-				huh.NewGroup(
-					huh.NewInput().
-						Title("Remote Path").
-						Description("File or directory to download").
-						Value(&remotePath).
-						Placeholder("/home/user/myfile.txt"),
-
-					huh.NewInput().
-						Title("Local Path").
-						Description("Destination path on local machine").
-						Value(&localPath).
-						Placeholder("./"),
-				),
-			)
-
-			if err := form.Run(); err != nil {
+			server, container, err := pickTarget(pullServer, pullContainer)
+			if err != nil {
+				return err
+			}
+			target = remoteTarget{Server: server, Container: container, Path: remoteSpec}
+		}
+
+		// 빠진 경로만 입력
+		var fields []huh.Field
+		if target.Path == "" {
+			fields = append(fields, huh.NewInput().
+				Title("Remote Path").
+				Description("File or directory to download").
+				Value(&target.Path).
+				Placeholder("/home/user/myfile.txt"))
+		}
+		if localPath == "" {
+			fields = append(fields, huh.NewInput().
+				Title("Local Path").
+				Description("Destination path on local machine").
+				Value(&localPath).
+				Placeholder("./"))
+		}
+		if len(fields) > 0 {
+			if err := huh.NewForm(huh.NewGroup(fields...)).Run(); err != nil {
 				return err
 			}
 		}
+		if target.Path == "" {
+			return fmt.Errorf("❌ No remote path given")
+		}
+
+		server := target.Server
+		if err := remotessh.TrustHostKey(server); err != nil {
+			return err
+		}
+		config.RecordServerUse(server.ServerName)
 
 		// 전송 실행
-		fmt.Printf("\n📥 Downloading %s:%s → %s\n\n", server.ServerName, remotePath, localPath)
+		fmt.Printf("\n📥 Downloading %s → %s\n\n", target, localPath)
 
-		if err := remotessh.Download(server, remotePath, localPath); err != nil {
+		opts, changes := transferOptions()
+		err = runTransfer(opts, func(ctx context.Context, opts remotessh.TransferOptions) error {
+			return remotessh.Download(ctx, server, target.Container, target.Path, localPath, opts)
+		})
+		if err != nil {
 			return err
 		}
 
+		if transferDryRun {
+			fmt.Println(transferSummary(*changes))
+			return nil
+		}
 		fmt.Println("\n✅ Download complete")
 		return nil
 	},
 }
 
 func init() {
+	pullCmd.Flags().StringVarP(&pullServer, "server", "s", "", "Server to download from")
+	pullCmd.Flags().StringVarP(&pullContainer, "container", "c", "", "Container on the server to download from")
+	addTransferFlags(pullCmd)
+	addSelectorFlags(pullCmd)
 	rootCmd.AddCommand(pullCmd)
 }
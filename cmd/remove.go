@@ -18,28 +18,17 @@ var removeCmd = &cobra.Command{
 			return nil
 		}
 
-		// 서버 선택 옵션 생성
-		options := make([]huh.Option[int], len(config.Servers))
-		for i, server := range config.Servers {
-			options[i] = huh.NewOption(
-				fmt.Sprintf("%s (%s@%s)", server.ServerName, server.Username, server.HostIp),
-				i,
-			)
+		// 서버 선택
+		server, err := SelectServer()
+		if err != nil {
+			return err
 		}
 
-		var selectedIndex int
 		var confirm bool
-
 		form := huh.NewForm(
-			huh.NewGroup(
-				huh.NewSelect[int]().
-					Title("Select server to remove").
-					Options(options...).
-					Value(&selectedIndex),
-			),
 			huh.NewGroup(
 				huh.NewConfirm().
-					Title("Are you sure?").
+					Title(fmt.Sprintf("Remove '%s'?", server.ServerName)).
 					Description("This action cannot be undone.").
 					Value(&confirm),
 			),
@@ -55,19 +44,20 @@ var removeCmd = &cobra.Command{
 		}
 
 		// 서버 삭제
-		removedServer := config.Servers[selectedIndex]
-		config.Servers = append(config.Servers[:selectedIndex], config.Servers[selectedIndex+1:]...)
+		index := serverIndex(server.ServerName)
+		config.Servers = append(config.Servers[:index], config.Servers[index+1:]...)
 
-		config.ServerConfig.Set("servers", config.Servers)
-		if err := config.ServerConfig.WriteConfig(); err != nil {
-			return fmt.Errorf("failed to save: %w", err)
+		if err := config.SaveServers(); err != nil {
+			return err
 		}
+		config.ForgetHistory(server.ServerName)
 
-		fmt.Printf("✅ Server '%s' removed successfully!\n", removedServer.ServerName)
+		fmt.Printf("✅ Server '%s' removed successfully!\n", server.ServerName)
 		return nil
 	},
 }
 
 func init() {
+	addSelectorFlags(removeCmd)
 	rootCmd.AddCommand(removeCmd)
 }
@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"remotelink/config"
+	"remotelink/models"
+	remotessh "remotelink/ssh"
 
 	"github.com/spf13/cobra"
 )
@@ -12,16 +15,90 @@ var rootCmd = &cobra.Command{
 	Use:   "remotelink",
 	Short: "",
 	Long:  "",
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := remotessh.ValidateBackend(remotessh.DefaultBackend); err != nil {
+			return err
+		}
 		config.LoadServers()
+		// 점프 호스트 이름을 서버 정보로 해석
+		remotessh.ResolveJumps = config.JumpChain
+
+		for _, server := range config.Servers {
+			if err := validateServer(server); err != nil {
+				return fmt.Errorf("invalid config for server '%s': %w", server.ServerName, err)
+			}
+		}
+		return nil
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 
 	},
 }
 
+func init() {
+	rootCmd.PersistentFlags().StringVar(&remotessh.DefaultBackend, "backend", remotessh.BackendNative,
+		"SSH backend for servers without ssh_backend (native or exec)")
+}
+
+// validateServer checks the settings of a configured server.
+func validateServer(server models.Server) error {
+	if server.SSHBackend != "" {
+		if err := remotessh.ValidateBackend(server.SSHBackend); err != nil {
+			return err
+		}
+	}
+	if server.HostKeyPolicy != "" {
+		if err := remotessh.ValidateHostKeyPolicy(server.HostKeyPolicy); err != nil {
+			return err
+		}
+	}
+	if server.Transfer != "" {
+		if err := remotessh.ValidateTransfer(server.Transfer); err != nil {
+			return err
+		}
+	}
+	if server.ContainerRuntime != "" {
+		if err := remotessh.ValidateRuntime(server.ContainerRuntime); err != nil {
+			return err
+		}
+	}
+	if _, err := config.JumpChain(server); err != nil {
+		return err
+	}
+
+	names := map[string]bool{}
+	for _, forward := range server.Forwards {
+		if forward.Name == "" {
+			return fmt.Errorf("forward preset without a name")
+		}
+		if names[forward.Name] {
+			return fmt.Errorf("duplicate forward preset '%s'", forward.Name)
+		}
+		names[forward.Name] = true
+
+		if err := remotessh.ValidateForward(forward); err != nil {
+			return fmt.Errorf("forward preset '%s': %w", forward.Name, err)
+		}
+	}
+	return nil
+}
+
+// exitError makes Execute exit with a remote command's own status. The command has already
+// printed its output, so nothing more is printed.
+type exitError struct {
+	code int
+}
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
+		var exit exitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.code)
+		}
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"remotelink/config"
+	"remotelink/models"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	selectGroup string
+	selectTags  []string
+)
+
+// addSelectorFlags registers --group and --tag, which narrow the servers a command picks from.
+func addSelectorFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&selectGroup, "group", "g", "", "Only pick from servers in this group")
+	cmd.Flags().StringSliceVarP(&selectTags, "tag", "t", nil, "Only pick from servers with this tag (repeatable)")
+}
+
+// selectorActive reports whether --group or --tag was given.
+func selectorActive() bool {
+	return selectGroup != "" || len(selectTags) > 0
+}
+
+// candidateServers returns the configured servers matching --group and --tag.
+func candidateServers() ([]models.Server, error) {
+	servers := config.FilterServers(selectGroup, selectTags)
+	if len(servers) == 0 && selectorActive() {
+		return nil, fmt.Errorf("no servers match %s", selectorString())
+	}
+	return servers, nil
+}
+
+func selectorString() string {
+	var parts []string
+	if selectGroup != "" {
+		parts = append(parts, "group '"+selectGroup+"'")
+	}
+	for _, tag := range selectTags {
+		parts = append(parts, "tag '"+tag+"'")
+	}
+	return strings.Join(parts, " and ")
+}
+
+// serverLabel is the one-line description of a server used in pickers.
+func serverLabel(server models.Server) string {
+	label := fmt.Sprintf("%-20s %s@%s:%d",
+		server.ServerName,
+		server.Username,
+		server.HostIp,
+		server.Port)
+
+	// 컨테이너 개수 표시
+	if len(server.Containers) > 0 {
+		label += fmt.Sprintf(" 🐳 %d containers", len(server.Containers))
+	}
+	if len(server.Tags) > 0 {
+		label += "  #" + strings.Join(server.Tags, " #")
+	}
+	return label
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"remotelink/config"
@@ -10,67 +11,134 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	sendServer    string
+	sendContainer string
+)
+
 var sendCmd = &cobra.Command{
-	Use:     "send [local-path] [remote-path]",
-	Short:   "Upload file or directory to remote server via scp",
-	Aliases: []string{"upload", "up"},
+	Use:   "send [local-path] [[server[/container]:]remote-path]",
+	Short: "Upload file or directory to remote server via SFTP, scp or rsync",
+	Long: `Upload file or directory to remote server via SFTP, scp or rsync.
+
+The destination can name the server (and optionally a container on it) scp-style,
+or they can be given with --server and --container.
+Anything missing is asked for interactively.
+
+  remotelink send ./build prod:/opt/app
+  remotelink send ./config.yml prod/web:/app/config.yml
+  remotelink send --server prod ./build /opt/app
+
+By default files go over SFTP with a progress bar per file. An interrupted upload
+resumes where it stopped when run again, and every file is verified by size,
+or by SHA-256 with --checksum. Servers without SFTP fall back to scp.
+
+With --transfer rsync (or "transfer: rsync" on the server) only changed files are sent.
+rsync also honors --delete, --exclude and a .remotelinkignore file in the source directory;
+--dry-run lists what would change. Without rsync on the server everything is copied over SFTP.
+
+  remotelink send ./build prod:/opt/app --delete --exclude node_modules
+  remotelink send ./build prod:/opt/app --dry-run`,
+	Aliases:      []string{"upload", "up"},
+	SilenceUsage: true,
+	Args:         cobra.MaximumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(config.Servers) == 0 {
 			fmt.Println("❌ No servers configured")
 			return nil
 		}
 
-		// 서버 선택
-		server, err := SelectServer()
+		var localPath, remoteSpec string
+		if len(args) > 0 {
+			localPath = args[0]
+		}
+		if len(args) > 1 {
+			remoteSpec = args[1]
+		}
+
+		// 서버 결정: server:path 주소 → --server → 선택 화면
+		target, isRemote, err := parseRemoteTarget(remoteSpec)
 		if err != nil {
 			return err
 		}
-
-		// 경로 입력
-		var localPath, remotePath string
-
-		if len(args) >= 2 {
-			localPath = args[0]
-			remotePath = args[1]
+		if isRemote {
+			if sendServer != "" && sendServer != target.Server.ServerName {
+				return fmt.Errorf("--server '%s' conflicts with destination '%s'", sendServer, remoteSpec)
+			}
+			switch {
+			case target.Container == "":
+				target.Container = sendContainer
+			case sendContainer != "" && sendContainer != target.Container:
+				return fmt.Errorf("--container '%s' conflicts with destination '%s'", sendContainer, remoteSpec)
+			}
 		} else {
-			form := huh.NewForm(
-				huh.NewGroup(
-					huh.NewInput().
-						Title("Local Path").
-						Description("File or directory to upload").
-						Value(&localPath).
-						Placeholder("./myfile.txt"),
-
-					huh.NewInput().
-						Title("Remote Path").
-						Description("Destination path on remote server").
-						Value(&remotePath).
-						Placeholder("/home/user/"),
-				),
-			)
-
-			if err := form.Run(); err != nil {
+			server, container, err := pickTarget(sendServer, sendContainer)
+			if err != nil {
+				return err
+			}
+			target = remoteTarget{Server: server, Container: container, Path: remoteSpec}
+		}
+
+		// 빠진 경로만 입력
+		var fields []huh.Field
+		if localPath == "" {
+			fields = append(fields, huh.NewInput().
+				Title("Local Path").
+				Description("File or directory to upload").
+				Value(&localPath).
+				Placeholder("./myfile.txt"))
+		}
+		if target.Path == "" {
+			fields = append(fields, huh.NewInput().
+				Title("Remote Path").
+				Description("Destination path on remote server").
+				Value(&target.Path).
+				Placeholder("/home/user/"))
+		}
+		if len(fields) > 0 {
+			if err := huh.NewForm(huh.NewGroup(fields...)).Run(); err != nil {
 				return err
 			}
 		}
+		if target.Path == "" {
+			return fmt.Errorf("❌ No remote path given")
+		}
 
 		// 로컬 파일 존재 확인
 		if _, err := os.Stat(localPath); os.IsNotExist(err) {
 			return fmt.Errorf("❌ Local path not found: %s", localPath)
 		}
 
+		server := target.Server
+		if err := remotessh.TrustHostKey(server); err != nil {
+			return err
+		}
+		config.RecordServerUse(server.ServerName)
+
 		// 전송 실행
-		fmt.Printf("\n📤 Uploading %s → %s:%s\n\n", localPath, server.ServerName, remotePath)
+		fmt.Printf("\n📤 Uploading %s → %s\n\n", localPath, target)
 
-		if err := remotessh.Upload(server, localPath, remotePath); err != nil {
+		opts, changes := transferOptions()
+		err = runTransfer(opts, func(ctx context.Context, opts remotessh.TransferOptions) error {
+			return remotessh.Upload(ctx, server, target.Container, localPath, target.Path, opts)
+		})
+		if err != nil {
 			return err
 		}
 
+		if transferDryRun {
+			fmt.Println(transferSummary(*changes))
+			return nil
+		}
 		fmt.Println("\n✅ Upload complete")
 		return nil
 	},
 }
 
 func init() {
+	sendCmd.Flags().StringVarP(&sendServer, "server", "s", "", "Server to upload to")
+	sendCmd.Flags().StringVarP(&sendContainer, "container", "c", "", "Container on the server to upload to")
+	addTransferFlags(sendCmd)
+	addSelectorFlags(sendCmd)
 	rootCmd.AddCommand(sendCmd)
 }
@@ -0,0 +1,452 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"remotelink/config"
+	"remotelink/models"
+	remotessh "remotelink/ssh"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsOnce     bool
+	statsJSON     bool
+	statsSort     string
+	statsInterval time.Duration
+)
+
+// statsHistoryLength is how many samples the sparklines show.
+const statsHistoryLength = 20
+
+// statsSortKeys are the --sort columns, in the order the s key cycles through them.
+var statsSortKeys = []string{"cpu", "mem", "net", "block", "pids", "name"}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [server[/container]...] [container...]",
+	Short: "Show live CPU, memory, network and block I/O of containers",
+	Long: `Show the resource usage of containers in a live, full-screen table with sparklines of
+recent CPU and memory use.
+
+Each argument is a server, a server/container pair, or a container on the server named before it.
+--group and --tag add servers, and containers from every server are shown in one view. Servers
+without named containers show all of their running containers.
+
+--once prints a single sample as a table instead, or with --json one JSON object per container.
+
+Keys: ↑/↓ move · s change sort · r reverse sort · q quit
+
+  remotelink stats prod
+  remotelink stats prod web db --sort mem
+  remotelink stats --group web --once --json | jq .`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(config.Servers) == 0 {
+			fmt.Println("❌ No servers configured")
+			return nil
+		}
+		if statsJSON && !statsOnce {
+			return fmt.Errorf("--json requires --once")
+		}
+		if !slices.Contains(statsSortKeys, statsSort) {
+			return fmt.Errorf("unknown --sort %q (expected one of %s)", statsSort, strings.Join(statsSortKeys, ", "))
+		}
+		if statsInterval < time.Second {
+			return fmt.Errorf("--interval must be at least 1s")
+		}
+
+		servers, containers, err := resolveContainerArgs(args)
+		if err != nil {
+			return err
+		}
+		for _, server := range servers {
+			for _, name := range containers[server.ServerName] {
+				if strings.HasPrefix(name, models.PodRefPrefix) {
+					return fmt.Errorf("stats are not available for pod '%s'", name)
+				}
+			}
+			if err := remotessh.TrustHostKey(server); err != nil {
+				return err
+			}
+		}
+
+		if statsOnce {
+			return printStatsOnce(servers, containers)
+		}
+		return runStatsView(servers, containers)
+	},
+}
+
+// statsRow is one container's latest sample. It is also the --json output.
+type statsRow struct {
+	Server string `json:"server"`
+	remotessh.ContainerStats
+}
+
+// key identifies the container across samples.
+func (r statsRow) key() string {
+	return r.Server + "/" + r.Name
+}
+
+// sortStatsRows orders rows by the sort key, largest first unless reversed. Ties and "name"
+// sort by server and container.
+func sortStatsRows(rows []statsRow, key string, reverse bool) {
+	value := func(r statsRow) float64 {
+		switch key {
+		case "cpu":
+			return r.CPUPercent
+		case "mem":
+			return float64(r.MemUsage)
+		case "net":
+			return float64(r.NetRx + r.NetTx)
+		case "block":
+			return float64(r.BlockRead + r.BlockWrite)
+		case "pids":
+			return float64(r.PIDs)
+		}
+		return 0
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if reverse {
+			a, b = b, a
+		}
+		if va, vb := value(a), value(b); va != vb {
+			return va > vb
+		}
+		return a.key() < b.key()
+	})
+}
+
+// printStatsOnce samples every server once and prints a table, or JSON lines with --json.
+func printStatsOnce(servers []models.Server, containers map[string][]string) error {
+	samples := make([][]remotessh.ContainerStats, len(servers))
+	errs := make([]error, len(servers))
+	fetch := func() {
+		var wg sync.WaitGroup
+		for i, server := range servers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				samples[i], errs[i] = remotessh.FetchStats(server, containers[server.ServerName])
+			}()
+		}
+		wg.Wait()
+	}
+
+	if statsJSON {
+		fetch()
+	} else if err := spinner.New().Title("Sampling container stats...").Action(fetch).Run(); err != nil {
+		return err
+	}
+
+	var rows []statsRow
+	failed := 0
+	for i, server := range servers {
+		if errs[i] != nil {
+			failed++
+			fmt.Fprintln(os.Stderr, failedStyle.Render(fmt.Sprintf("✗ %s: %s", server.ServerName, firstLine(errs[i].Error()))))
+			continue
+		}
+		for _, stats := range samples[i] {
+			rows = append(rows, statsRow{Server: server.ServerName, ContainerStats: stats})
+		}
+	}
+	sortStatsRows(rows, statsSort, false)
+
+	if statsJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		for _, row := range rows {
+			encoder.Encode(row)
+		}
+	} else if len(rows) > 0 {
+		cells := make([][]string, len(rows))
+		for i, row := range rows {
+			cells[i] = []string{row.Server, row.Name, fmt.Sprintf("%.1f%%", row.CPUPercent),
+				statsMemory(row.ContainerStats), fmt.Sprintf("%.1f%%", row.MemPercent),
+				statsPair(row.NetRx, row.NetTx), statsPair(row.BlockRead, row.BlockWrite), fmt.Sprintf("%d", row.PIDs)}
+		}
+		fmt.Println(newTable(-1, []string{"SERVER", "CONTAINER", "CPU", "MEMORY", "MEM %", "NET I/O", "BLOCK I/O", "PIDS"}, cells).Render())
+	} else if failed < len(servers) {
+		fmt.Println("No running containers")
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d servers failed", failed, len(servers))
+	}
+	return nil
+}
+
+// runStatsView streams stats from every server into the live view until it is closed, then
+// stops the remote samplers.
+func runStatsView(servers []models.Server, containers map[string][]string) error {
+	model := statsModel{
+		servers: len(servers),
+		history: map[string]*statsHistory{},
+		errs:    map[string]error{},
+		sortKey: slices.Index(statsSortKeys, statsSort),
+	}
+	program := tea.NewProgram(model, tea.WithAltScreen())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for _, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := remotessh.StreamStats(ctx, server, containers[server.ServerName], statsInterval, func(stats []remotessh.ContainerStats) {
+				program.Send(statsSampleMsg{server: server.ServerName, stats: stats})
+			})
+			if err != nil && ctx.Err() == nil {
+				program.Send(statsErrMsg{server: server.ServerName, err: err})
+			}
+		}()
+	}
+
+	_, err := program.Run()
+	cancel()
+	wg.Wait()
+	return err
+}
+
+// statsHistory keeps a container's recent samples for the sparklines.
+type statsHistory struct {
+	cpu []float64
+	mem []float64
+}
+
+func (h *statsHistory) add(stats remotessh.ContainerStats) {
+	h.cpu = append(h.cpu, stats.CPUPercent)
+	h.mem = append(h.mem, stats.MemPercent)
+	if len(h.cpu) > statsHistoryLength {
+		h.cpu = h.cpu[1:]
+		h.mem = h.mem[1:]
+	}
+}
+
+type statsModel struct {
+	servers int
+	rows    []statsRow
+	history map[string]*statsHistory
+	// errs holds the servers whose stream failed, shown under the table.
+	errs    map[string]error
+	sortKey int
+	reverse bool
+	cursor  int
+	offset  int
+	width   int
+	height  int
+	updated time.Time
+}
+
+type statsSampleMsg struct {
+	server string
+	stats  []remotessh.ContainerStats
+}
+
+type statsErrMsg struct {
+	server string
+	err    error
+}
+
+func (m statsModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m statsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.clampOffset()
+
+	case statsSampleMsg:
+		// 서버의 행을 새 샘플로 교체하고, 사라진 컨테이너의 기록은 삭제
+		m.rows = slices.DeleteFunc(m.rows, func(r statsRow) bool { return r.Server == msg.server })
+		seen := map[string]bool{}
+		for _, stats := range msg.stats {
+			row := statsRow{Server: msg.server, ContainerStats: stats}
+			history, ok := m.history[row.key()]
+			if !ok {
+				history = &statsHistory{}
+				m.history[row.key()] = history
+			}
+			history.add(stats)
+			seen[row.key()] = true
+			m.rows = append(m.rows, row)
+		}
+		for key := range m.history {
+			if strings.HasPrefix(key, msg.server+"/") && !seen[key] {
+				delete(m.history, key)
+			}
+		}
+		m.updated = time.Now()
+		delete(m.errs, msg.server)
+		m.sort()
+
+	case statsErrMsg:
+		m.errs[msg.server] = msg.err
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "up", "k":
+			m.cursor = max(m.cursor-1, 0)
+			m.clampOffset()
+		case "down", "j":
+			m.cursor = max(min(m.cursor+1, len(m.rows)-1), 0)
+			m.clampOffset()
+		case "s":
+			m.sortKey = (m.sortKey + 1) % len(statsSortKeys)
+			m.sort()
+		case "r":
+			m.reverse = !m.reverse
+			m.sort()
+		}
+	}
+	return m, nil
+}
+
+func (m *statsModel) sort() {
+	sortStatsRows(m.rows, statsSortKeys[m.sortKey], m.reverse)
+	m.cursor = min(m.cursor, max(len(m.rows)-1, 0))
+	m.clampOffset()
+}
+
+// visibleRows is how many table rows fit next to the header, errors and help.
+func (m statsModel) visibleRows() int {
+	if m.height == 0 {
+		return max(len(m.rows), 1)
+	}
+	return max(m.height-7-len(m.errs), 3)
+}
+
+func (m *statsModel) clampOffset() {
+	visible := m.visibleRows()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+visible {
+		m.offset = m.cursor - visible + 1
+	}
+}
+
+func (m statsModel) View() string {
+	var b strings.Builder
+
+	order := "↓"
+	if m.reverse {
+		order = "↑"
+	}
+	b.WriteString(titleStyle.Render(fmt.Sprintf("remotelink stats — %d containers on %d servers · every %s · sorted by %s %s",
+		len(m.rows), m.servers, statsInterval, statsSortKeys[m.sortKey], order)))
+	b.WriteString("\n")
+
+	if len(m.rows) == 0 {
+		if m.updated.IsZero() && len(m.errs) < m.servers {
+			b.WriteString(valueStyle.Render("Sampling container stats...") + "\n")
+		} else {
+			b.WriteString(valueStyle.Render("No running containers") + "\n")
+		}
+	} else {
+		end := min(m.offset+m.visibleRows(), len(m.rows))
+		var cells [][]string
+		for i := m.offset; i < end; i++ {
+			cells = append(cells, m.cells(m.rows[i]))
+		}
+		b.WriteString(newTable(m.cursor-m.offset, []string{
+			"SERVER", "CONTAINER", "CPU", "CPU HISTORY", "MEMORY", "MEM HISTORY", "NET I/O", "BLOCK I/O", "PIDS"}, cells).Render())
+		b.WriteString("\n")
+		if len(m.rows) > m.visibleRows() {
+			b.WriteString(dashboardHelpStyle.Render(fmt.Sprintf("  %d/%d", m.cursor+1, len(m.rows))))
+			b.WriteString("\n")
+		}
+	}
+
+	names := make([]string, 0, len(m.errs))
+	for name := range m.errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("%s: %s", name, firstLine(m.errs[name].Error()))) + "\n")
+	}
+
+	b.WriteString(dashboardHelpStyle.Render("↑/↓ move • s sort • r reverse • q quit"))
+	return b.String()
+}
+
+// cells renders a row's live table columns.
+func (m statsModel) cells(row statsRow) []string {
+	history := m.history[row.key()]
+	return []string{
+		row.Server,
+		row.Name,
+		statsPercentStyle(row.CPUPercent).Render(fmt.Sprintf("%6.1f%%", row.CPUPercent)),
+		sparkline(history.cpu, 100),
+		statsPercentStyle(row.MemPercent).Render(fmt.Sprintf("%s (%.0f%%)", statsMemory(row.ContainerStats), row.MemPercent)),
+		sparkline(history.mem, 100),
+		statsPair(row.NetRx, row.NetTx),
+		statsPair(row.BlockRead, row.BlockWrite),
+		fmt.Sprintf("%d", row.PIDs),
+	}
+}
+
+// statsPercentStyle colors a usage percentage like usageBar does.
+func statsPercentStyle(percent float64) lipgloss.Style {
+	switch {
+	case percent >= 90:
+		return failedStyle
+	case percent >= 70:
+		return warnStyle
+	}
+	return lipgloss.NewStyle()
+}
+
+// statsMemory formats memory as "usage / limit".
+func statsMemory(stats remotessh.ContainerStats) string {
+	return fmt.Sprintf("%s / %s", humanize.IBytes(stats.MemUsage), humanize.IBytes(stats.MemLimit))
+}
+
+// statsPair formats a pair of byte counters such as received and sent.
+func statsPair(first, second uint64) string {
+	return fmt.Sprintf("%s / %s", humanize.Bytes(first), humanize.Bytes(second))
+}
+
+// sparkline draws values as block characters scaled to ceiling, or to the largest value when that
+// is higher, padded to statsHistoryLength so the column does not jump.
+func sparkline(values []float64, ceiling float64) string {
+	for _, v := range values {
+		ceiling = max(ceiling, v)
+	}
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", statsHistoryLength-len(values)))
+	for _, v := range values {
+		level := int(v / ceiling * float64(len(sparkBlocks)-1))
+		b.WriteRune(sparkBlocks[min(max(level, 0), len(sparkBlocks)-1)])
+	}
+	return okStyle.Render(b.String())
+}
+
+func init() {
+	statsCmd.Flags().BoolVar(&statsOnce, "once", false, "Print a single sample instead of the live view")
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "With --once, print one JSON object per container")
+	statsCmd.Flags().StringVar(&statsSort, "sort", "cpu", "Sort by "+strings.Join(statsSortKeys, ", "))
+	statsCmd.Flags().DurationVar(&statsInterval, "interval", 2*time.Second, "How often to sample in the live view")
+	addSelectorFlags(statsCmd)
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"remotelink/config"
+	remotessh "remotelink/ssh"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncServer    string
+	syncContainer string
+	syncWatch     bool
+	syncExcludes  []string
+	syncDebounce  time.Duration
+	syncRun       string
+)
+
+// syncListLimit is how many paths a status line names before summarizing the rest.
+const syncListLimit = 3
+
+var syncCmd = &cobra.Command{
+	Use:   "sync <local-dir> [[server[/container]:]remote-dir]",
+	Short: "Push a local directory to a server or container, optionally on every change",
+	Long: `Push the contents of a local directory into a directory on a server or container.
+
+With --watch the directory is pushed once and then watched: changes are collected
+until nothing happens for --debounce and sent as one batch, and deleted or renamed
+files are removed on the other side. Paths in .remotelinkignore and --exclude are skipped.
+
+--run executes a command on the server after every batch, e.g. to restart the app.
+
+  remotelink sync --watch ./src prod:/opt/app
+  remotelink sync --watch ./src prod/web:/app --run "docker restart web"`,
+	Args:         cobra.RangeArgs(1, 2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(config.Servers) == 0 {
+			fmt.Println("❌ No servers configured")
+			return nil
+		}
+
+		localDir := args[0]
+		if info, err := os.Stat(localDir); err != nil || !info.IsDir() {
+			return fmt.Errorf("❌ Local directory not found: %s", localDir)
+		}
+		var remoteSpec string
+		if len(args) > 1 {
+			remoteSpec = args[1]
+		}
+
+		// 서버 결정: server:path 주소 → --server → 선택 화면
+		target, isRemote, err := parseRemoteTarget(remoteSpec)
+		if err != nil {
+			return err
+		}
+		if isRemote {
+			if syncServer != "" && syncServer != target.Server.ServerName {
+				return fmt.Errorf("--server '%s' conflicts with destination '%s'", syncServer, remoteSpec)
+			}
+			switch {
+			case target.Container == "":
+				target.Container = syncContainer
+			case syncContainer != "" && syncContainer != target.Container:
+				return fmt.Errorf("--container '%s' conflicts with destination '%s'", syncContainer, remoteSpec)
+			}
+		} else {
+			server, container, err := pickTarget(syncServer, syncContainer)
+			if err != nil {
+				return err
+			}
+			target = remoteTarget{Server: server, Container: container, Path: remoteSpec}
+		}
+
+		if target.Path == "" {
+			err := huh.NewInput().
+				Title("Remote Directory").
+				Description("Directory to keep in sync with " + localDir).
+				Value(&target.Path).
+				Placeholder("/opt/app").
+				Run()
+			if err != nil {
+				return err
+			}
+		}
+		if target.Path == "" {
+			return fmt.Errorf("❌ No remote directory given")
+		}
+
+		if err := remotessh.TrustHostKey(target.Server); err != nil {
+			return err
+		}
+		config.RecordServerUse(target.Server.ServerName)
+
+		ignore, err := remotessh.LoadIgnoreRules(localDir, syncExcludes)
+		if err != nil {
+			return err
+		}
+
+		s := &syncer{target: target, root: localDir, ignore: ignore}
+		fmt.Printf("\n🔄 Syncing %s → %s\n\n", localDir, target)
+
+		// 처음에는 디렉터리 전체를 한 번 전송
+		entries, err := os.ReadDir(localDir)
+		if err != nil {
+			return err
+		}
+		var all []string
+		for _, entry := range entries {
+			if !ignore.Match(entry.Name(), entry.IsDir()) {
+				all = append(all, entry.Name())
+			}
+		}
+		err = s.push(all, nil)
+		if !syncWatch {
+			return err
+		}
+
+		// 처음 전송이 실패하면 감시 중 다음 묶음과 함께 다시 전송
+		var pending []string
+		if err != nil {
+			pending = all
+		}
+		return s.watch(pending)
+	},
+}
+
+// syncer pushes batches of changes below root to target.
+type syncer struct {
+	target remoteTarget
+	root   string
+	ignore *remotessh.IgnoreRules
+}
+
+// watch pushes changes below root until interrupted, along with the pending paths of a failed
+// earlier push. A failed batch is retried with the next one.
+func (s *syncer) watch(pending []string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := s.addTree(watcher, s.root); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Println()
+	fmt.Println(transferDimStyle.Render(fmt.Sprintf("Watching %s · Ctrl+C to stop", s.root)))
+	if len(pending) > 0 {
+		fmt.Println(warnStyle.Render("Initial sync incomplete · retried with the next change"))
+	}
+
+	changed, removed := map[string]bool{}, map[string]bool{}
+	for _, rel := range pending {
+		changed[rel] = true
+	}
+	debounce := time.NewTimer(syncDebounce)
+	debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			rel, err := filepath.Rel(s.root, event.Name)
+			if err != nil || rel == "." {
+				continue
+			}
+			rel = filepath.ToSlash(rel)
+
+			info, statErr := os.Lstat(event.Name)
+			isDir := statErr == nil && info.IsDir()
+			if s.ignore.Match(rel, isDir) {
+				continue
+			}
+
+			if statErr != nil {
+				// 삭제되었거나 다른 이름으로 옮겨짐. 새 이름은 Create 이벤트로 들어옴
+				delete(changed, rel)
+				removed[rel] = true
+			} else {
+				delete(removed, rel)
+				changed[rel] = true
+				if isDir && event.Has(fsnotify.Create) {
+					if err := s.addTree(watcher, event.Name); err != nil {
+						fmt.Println(failedStyle.Render("✗ " + err.Error()))
+					}
+				}
+			}
+			debounce.Reset(syncDebounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Println(failedStyle.Render("✗ watch: " + err.Error()))
+
+		case <-debounce.C:
+			changedList, removedList := s.batch(changed, removed)
+			clear(changed)
+			clear(removed)
+
+			if err := s.push(changedList, removedList); err != nil {
+				// 실패한 묶음은 다음 변경과 함께 다시 전송
+				for _, rel := range changedList {
+					changed[rel] = true
+				}
+				for _, rel := range removedList {
+					removed[rel] = true
+				}
+			}
+		}
+	}
+}
+
+// addTree watches dir and every directory below it that is not ignored.
+func (s *syncer) addTree(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		if rel, _ := filepath.Rel(s.root, p); rel != "." && s.ignore.Match(filepath.ToSlash(rel), true) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(p)
+	})
+}
+
+// batch turns the pending events into sorted path lists. Paths that vanished since are removed
+// instead, and paths inside a directory that is sent or removed as a whole are dropped.
+func (s *syncer) batch(changed, removed map[string]bool) (changedList, removedList []string) {
+	for rel := range changed {
+		if _, err := os.Lstat(filepath.Join(s.root, filepath.FromSlash(rel))); err != nil {
+			removed[rel] = true
+			continue
+		}
+		changedList = append(changedList, rel)
+	}
+	for rel := range removed {
+		removedList = append(removedList, rel)
+	}
+	return pruneNested(changedList), pruneNested(removedList)
+}
+
+// pruneNested sorts rels and drops those below another path in the list.
+func pruneNested(rels []string) []string {
+	slices.Sort(rels)
+	set := map[string]bool{}
+	for _, rel := range rels {
+		set[rel] = true
+	}
+
+	var pruned []string
+	for _, rel := range rels {
+		nested := false
+		for dir := path.Dir(rel); dir != "." && !nested; dir = path.Dir(dir) {
+			nested = set[dir]
+		}
+		if !nested {
+			pruned = append(pruned, rel)
+		}
+	}
+	return pruned
+}
+
+// push sends one batch, runs --run after it and prints a status line for each.
+func (s *syncer) push(changed, removed []string) error {
+	if len(changed) == 0 && len(removed) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	err := remotessh.PushFiles(s.target.Server, s.target.Container, s.root, s.target.Path, changed, removed, s.ignore)
+	stamp := transferDimStyle.Render(time.Now().Format("15:04:05"))
+	if err != nil {
+		fmt.Printf("%s %s\n", stamp, failedStyle.Render("✗ "+firstLine(err.Error())))
+		return err
+	}
+
+	var counts []string
+	if len(changed) > 0 {
+		counts = append(counts, fmt.Sprintf("%d pushed", len(changed)))
+	}
+	if len(removed) > 0 {
+		counts = append(counts, fmt.Sprintf("%d removed", len(removed)))
+	}
+	fmt.Printf("%s %s %s  %s\n", stamp,
+		okStyle.Render("✓ "+strings.Join(counts, ", ")),
+		transferDimStyle.Render(fmt.Sprintf("in %s", time.Since(start).Round(time.Millisecond))),
+		describeSyncPaths(changed, removed))
+
+	if syncRun != "" {
+		s.runAfter()
+	}
+	return nil
+}
+
+// runAfter runs --run on the server with its output indented under the status line.
+func (s *syncer) runAfter() {
+	outMu := &sync.Mutex{}
+	prefix := transferDimStyle.Render("  │ ")
+	stdout := &linePrefixer{mu: outMu, out: os.Stdout, prefix: prefix}
+	stderr := &linePrefixer{mu: outMu, out: os.Stderr, prefix: prefix}
+
+	err := remotessh.BackendFor(s.target.Server).Stream(s.target.Server, syncRun, nil, stdout, stderr)
+	stdout.Flush()
+	stderr.Flush()
+
+	stamp := transferDimStyle.Render(time.Now().Format("15:04:05"))
+	if err != nil {
+		fmt.Printf("%s %s\n", stamp, failedStyle.Render("✗ "+syncRun+": "+firstLine(err.Error())))
+		return
+	}
+	fmt.Printf("%s %s\n", stamp, okStyle.Render("✓ ran "+syncRun))
+}
+
+// describeSyncPaths names the first few paths of a batch, e.g. "app.js, -old.css, +2 more".
+func describeSyncPaths(changed, removed []string) string {
+	names := slices.Clone(changed)
+	for _, rel := range removed {
+		names = append(names, "-"+rel)
+	}
+	if len(names) > syncListLimit {
+		names = append(names[:syncListLimit], fmt.Sprintf("+%d more", len(names)-syncListLimit))
+	}
+	return strings.Join(names, ", ")
+}
+
+func init() {
+	syncCmd.Flags().StringVarP(&syncServer, "server", "s", "", "Server to sync to")
+	syncCmd.Flags().StringVarP(&syncContainer, "container", "c", "", "Container on the server to sync to")
+	syncCmd.Flags().BoolVarP(&syncWatch, "watch", "w", false, "Keep pushing changes until interrupted")
+	syncCmd.Flags().StringArrayVar(&syncExcludes, "exclude", nil, "Skip paths matching a pattern, in addition to "+remotessh.IgnoreFileName)
+	syncCmd.Flags().DurationVar(&syncDebounce, "debounce", 300*time.Millisecond, "Quiet period before a batch of changes is pushed")
+	syncCmd.Flags().StringVar(&syncRun, "run", "", "Command to run on the server after each batch")
+	addSelectorFlags(syncCmd)
+	rootCmd.AddCommand(syncCmd)
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPruneNested(t *testing.T) {
+	tests := []struct {
+		name string
+		rels []string
+		want []string
+	}{
+		{name: "empty", rels: nil, want: nil},
+		{name: "unrelated paths are sorted", rels: []string{"b.txt", "a.txt"}, want: []string{"a.txt", "b.txt"}},
+		{name: "child of listed dir", rels: []string{"src/main.go", "src"}, want: []string{"src"}},
+		{name: "deeply nested", rels: []string{"src", "src/a/b/c.go", "src/a"}, want: []string{"src"}},
+		{name: "sibling with common prefix", rels: []string{"src", "src2/main.go"}, want: []string{"src", "src2/main.go"}},
+		{name: "unlisted parent", rels: []string{"src/a.go", "src/b.go"}, want: []string{"src/a.go", "src/b.go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pruneNested(tt.rels); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pruneNested(%q) = %q, want %q", tt.rels, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+var selectedStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#7D56F4"))
+
+// newTable builds the rounded, bold-headed table used by every listing. selected highlights
+// the first cell of that row, for views with a cursor; -1 highlights nothing.
+func newTable(selected int, headers []string, rows [][]string) *table.Table {
+	return table.New().
+		Border(lipgloss.RoundedBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4"))).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			style := lipgloss.NewStyle().Padding(0, 1)
+			if row == table.HeaderRow {
+				return style.Bold(true)
+			}
+			if row == selected && col == 0 {
+				return style.Inherit(selectedStyle)
+			}
+			return style
+		}).
+		Headers(headers...).
+		Rows(rows...)
+}
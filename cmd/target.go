@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"remotelink/config"
+	"remotelink/models"
+	"strings"
+)
+
+// remoteTarget is a parsed scp-like address: "server:path" or "server/container:path".
+type remoteTarget struct {
+	Server    models.Server
+	Container string
+	Path      string
+}
+
+// parseRemoteTarget parses spec as a remote address. ok is false when spec is a local path: it
+// has no ':', or what comes before the ':' holds a path separator or is a drive letter, so
+// "./file:name" stays local as with scp. Otherwise the prefix must name a configured server.
+func parseRemoteTarget(spec string) (target remoteTarget, ok bool, err error) {
+	host, remotePath, found := strings.Cut(spec, ":")
+	if !found || host == "" {
+		return remoteTarget{}, false, nil
+	}
+
+	serverName, container, _ := strings.Cut(host, "/")
+	server, found := config.FindServer(serverName)
+	if !found {
+		if strings.ContainsAny(host, `/\`) || isDriveLetter(host) {
+			return remoteTarget{}, false, nil
+		}
+		// 잘못 쓴 서버 이름을 로컬 경로로 취급하면 선택 화면이 열리고 주소 전체가 원격 경로가 됨
+		return remoteTarget{}, false, fmt.Errorf("server '%s' not found", serverName)
+	}
+
+	return remoteTarget{Server: server, Container: container, Path: remotePath}, true, nil
+}
+
+func isDriveLetter(s string) bool {
+	return len(s) == 1 && ('a' <= s[0] && s[0] <= 'z' || 'A' <= s[0] && s[0] <= 'Z')
+}
+
+// String formats the target the way it is written on the command line.
+func (t remoteTarget) String() string {
+	host := t.Server.ServerName
+	if t.Container != "" {
+		host += "/" + t.Container
+	}
+	return host + ":" + t.Path
+}
+
+// findServer looks up a configured server by name.
+func findServer(name string) (models.Server, error) {
+	server, found := config.FindServer(name)
+	if !found {
+		return models.Server{}, fmt.Errorf("server '%s' not found", name)
+	}
+	return server, nil
+}
+
+// pickTarget resolves the server and container for a transfer from the --server and --container
+// flags. Whatever is missing is asked for: the server with the picker, where the host or a
+// container can be chosen directly, and otherwise the container with selectContainer.
+func pickTarget(serverName, container string) (models.Server, string, error) {
+	if serverName != "" {
+		server, err := findServer(serverName)
+		return server, container, err
+	}
+	if container != "" {
+		server, err := SelectServer()
+		return server, container, err
+	}
+
+	server, container, chosen, err := selectServerTarget()
+	if err != nil || chosen {
+		return server, container, err
+	}
+	container, err = selectContainer(server, fmt.Sprintf("📍 Select target on %s", server.ServerName))
+	return server, container, err
+}
+
+// resolveContainerArgs resolves arguments that are servers, server/container pairs or containers
+// on the server named before them, adding the servers matched by --group and --tag. Without any
+// server the picker asks for one. containers holds the containers named for each server.
+func resolveContainerArgs(args []string) (servers []models.Server, containers map[string][]string, err error) {
+	containers = map[string][]string{}
+	addServer := func(server models.Server) {
+		if _, ok := containers[server.ServerName]; !ok {
+			servers = append(servers, server)
+			containers[server.ServerName] = nil
+		}
+	}
+
+	var current string
+	for _, arg := range args {
+		serverName, container, hasContainer := strings.Cut(arg, "/")
+		server, found := config.FindServer(serverName)
+		switch {
+		case found:
+			addServer(server)
+			current = serverName
+			if hasContainer && container != "" {
+				containers[serverName] = append(containers[serverName], container)
+			}
+		case current != "" && !hasContainer:
+			// 서버 이름이 아니면 앞에 나온 서버의 컨테이너
+			containers[current] = append(containers[current], arg)
+		default:
+			return nil, nil, fmt.Errorf("server '%s' not found", serverName)
+		}
+	}
+
+	if selectorActive() {
+		matched, err := candidateServers()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, server := range matched {
+			addServer(server)
+		}
+	}
+
+	if len(servers) == 0 {
+		server, err := SelectServer()
+		if err != nil {
+			return nil, nil, err
+		}
+		addServer(server)
+	}
+	return servers, containers, nil
+}
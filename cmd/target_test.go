@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"remotelink/config"
+	"remotelink/models"
+	"testing"
+)
+
+func TestParseRemoteTarget(t *testing.T) {
+	saved := config.Servers
+	config.Servers = []models.Server{{ServerName: "web"}, {ServerName: "db.internal"}}
+	t.Cleanup(func() { config.Servers = saved })
+
+	tests := []struct {
+		spec          string
+		wantOK        bool
+		wantErr       string
+		wantServer    string
+		wantContainer string
+		wantPath      string
+	}{
+		{spec: "web:/var/www", wantOK: true, wantServer: "web", wantPath: "/var/www"},
+		{spec: "web:", wantOK: true, wantServer: "web", wantPath: ""},
+		{spec: "web:relative/dir", wantOK: true, wantServer: "web", wantPath: "relative/dir"},
+		{spec: "web/nginx:/etc/nginx", wantOK: true, wantServer: "web", wantContainer: "nginx", wantPath: "/etc/nginx"},
+		{spec: "db.internal:a:b", wantOK: true, wantServer: "db.internal", wantPath: "a:b"},
+		{spec: "unknown:/tmp", wantErr: "server 'unknown' not found"},
+		{spec: "prdo:/var/log/x", wantErr: "server 'prdo' not found"},
+		{spec: "unknown/web:/tmp", wantOK: false},
+		{spec: "./web:/tmp", wantOK: false},
+		{spec: "local/path", wantOK: false},
+		{spec: "file.txt", wantOK: false},
+		{spec: ":/tmp", wantOK: false},
+		{spec: `C:\Users\me`, wantOK: false},
+		{spec: "c:/Users/me", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, ok, err := parseRemoteTarget(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("parseRemoteTarget(%q) error = %v, want %q", tt.spec, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRemoteTarget(%q) error = %v", tt.spec, err)
+			}
+			if ok != tt.wantOK {
+				t.Fatalf("parseRemoteTarget(%q) ok = %v, want %v", tt.spec, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got.Server.ServerName != tt.wantServer || got.Container != tt.wantContainer || got.Path != tt.wantPath {
+				t.Errorf("parseRemoteTarget(%q) = %s/%s:%s, want %s/%s:%s", tt.spec,
+					got.Server.ServerName, got.Container, got.Path, tt.wantServer, tt.wantContainer, tt.wantPath)
+			}
+			if got.String() != tt.spec {
+				t.Errorf("String() = %q, want %q", got.String(), tt.spec)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	remotessh "remotelink/ssh"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// send와 pull이 함께 쓰는 전송 옵션
+var (
+	transferMethod   string
+	transferDelete   bool
+	transferExcludes []string
+	transferChecksum bool
+	transferDryRun   bool
+)
+
+var transferDimStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262"))
+
+// transferBarWidth is the width of the progress bars, percentage included.
+const transferBarWidth = 40
+
+// errTransferInterrupted is returned when the user stops an SFTP transfer; partial files are kept.
+var errTransferInterrupted = errors.New("transfer interrupted; run the same command again to resume")
+
+// addTransferFlags registers the transfer method and rsync options on cmd.
+func addTransferFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&transferMethod, "transfer", "", "Transfer method: sftp, scp or rsync (default: server's transfer, else sftp)")
+	cmd.Flags().BoolVar(&transferDelete, "delete", false, "Delete destination files missing from the source (rsync)")
+	cmd.Flags().StringArrayVar(&transferExcludes, "exclude", nil, "Skip paths matching an rsync pattern, in addition to "+remotessh.IgnoreFileName)
+	cmd.Flags().BoolVar(&transferChecksum, "checksum", false, "Compare contents with rsync, verify every file by SHA-256 with sftp")
+	cmd.Flags().BoolVarP(&transferDryRun, "dry-run", "n", false, "List what would change without transferring (rsync)")
+}
+
+// transferOptions builds the options from the flags, printing each change rsync reports.
+// The returned counter holds the number of changes once the transfer is done.
+func transferOptions() (remotessh.TransferOptions, *int) {
+	changes := new(int)
+	return remotessh.TransferOptions{
+		Method:   transferMethod,
+		Delete:   transferDelete,
+		Excludes: transferExcludes,
+		Checksum: transferChecksum,
+		DryRun:   transferDryRun,
+		Change: func(change remotessh.TransferChange) {
+			*changes++
+			switch change.Kind {
+			case remotessh.ChangeCreate:
+				fmt.Println(okStyle.Render("  + " + change.Path))
+			case remotessh.ChangeDelete:
+				fmt.Println(failedStyle.Render("  - " + change.Path))
+			default:
+				fmt.Println(warnStyle.Render("  ~ " + change.Path))
+			}
+		},
+	}, changes
+}
+
+// transferSummary is printed instead of the completion message after a dry run.
+func transferSummary(changes int) string {
+	if changes == 0 {
+		return "\n✅ Dry run: already up to date"
+	}
+	return fmt.Sprintf("\n🔍 Dry run: %d change(s), nothing transferred", changes)
+}
+
+// runTransfer runs transfer with progress bars for SFTP files. The bars only start with the first
+// progress report, so rsync and scp output is never drawn over. Without a terminal, completed
+// files are printed one per line instead.
+func runTransfer(opts remotessh.TransferOptions, transfer func(context.Context, remotessh.TransferOptions) error) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		opts.Progress = func(p remotessh.TransferProgress) {
+			if p.Verified != "" {
+				fmt.Println(transferFileLine(p))
+			}
+		}
+		return transferResult(ctx, transfer(ctx, opts))
+	}
+
+	var (
+		program  *tea.Program
+		start    sync.Once
+		finished = make(chan tea.Model, 1)
+	)
+	opts.Progress = func(p remotessh.TransferProgress) {
+		start.Do(func() {
+			program = tea.NewProgram(newTransferModel(cancel))
+			go func() {
+				model, _ := program.Run()
+				finished <- model
+			}()
+		})
+		program.Send(transferProgressMsg(p))
+	}
+
+	err := transfer(ctx, opts)
+	if program != nil {
+		program.Send(transferDoneMsg{})
+		<-finished
+	}
+	return transferResult(ctx, err)
+}
+
+func transferResult(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return errTransferInterrupted
+	}
+	return err
+}
+
+type transferProgressMsg remotessh.TransferProgress
+
+type transferDoneMsg struct{}
+
+// transferModel draws a bar for the file in flight and one for the whole transfer.
+// Completed files scroll above it.
+type transferModel struct {
+	cancel  context.CancelFunc
+	fileBar progress.Model
+	allBar  progress.Model
+
+	current   remotessh.TransferProgress
+	fileStart time.Time
+	fileBase  int64
+	start     time.Time
+	totalBase int64
+	started   bool
+}
+
+func newTransferModel(cancel context.CancelFunc) transferModel {
+	return transferModel{
+		cancel:  cancel,
+		fileBar: progress.New(progress.WithDefaultGradient(), progress.WithWidth(transferBarWidth)),
+		allBar:  progress.New(progress.WithSolidFill("#7D56F4"), progress.WithWidth(transferBarWidth)),
+	}
+}
+
+func (m transferModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m transferModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.cancel()
+			return m, tea.Quit
+		}
+
+	case transferProgressMsg:
+		p := remotessh.TransferProgress(msg)
+		now := time.Now()
+		if !m.started {
+			m.started, m.start, m.totalBase = true, now, p.TotalDone
+		}
+		if p.Path != m.current.Path || m.fileStart.IsZero() {
+			m.fileStart, m.fileBase = now, p.Done
+		}
+		m.current = p
+
+		if p.Verified != "" {
+			// 완료된 파일은 진행 표시 위로 남김
+			m.fileStart = time.Time{}
+			return m, tea.Println(transferFileLine(p))
+		}
+
+	case transferDoneMsg:
+		m.current = remotessh.TransferProgress{}
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m transferModel) View() string {
+	p := m.current
+	if p.Path == "" || p.Verified != "" {
+		return ""
+	}
+
+	rate := transferRate(p.Done-m.fileBase, time.Since(m.fileStart))
+	view := p.Path + "\n"
+	if p.Size > 0 {
+		view += m.fileBar.ViewAs(ratio(p.Done, p.Size)) + "  " +
+			transferDimStyle.Render(fmt.Sprintf("%s / %s%s", humanize.Bytes(uint64(p.Done)), humanize.Bytes(uint64(max(p.Size, p.Done))),
+				transferSpeed(rate, p.Size-p.Done))) + "\n"
+	} else {
+		// 크기를 모르면 전송량과 속도만 표시
+		view += transferDimStyle.Render(fmt.Sprintf("%s%s", humanize.Bytes(uint64(p.Done)), transferSpeed(rate, 0))) + "\n"
+	}
+
+	if p.TotalSize > p.Size {
+		rate := transferRate(p.TotalDone-m.totalBase, time.Since(m.start))
+		view += m.allBar.ViewAs(ratio(p.TotalDone, p.TotalSize)) + "  " +
+			transferDimStyle.Render(fmt.Sprintf("total %s / %s%s", humanize.Bytes(uint64(p.TotalDone)), humanize.Bytes(uint64(p.TotalSize)),
+				transferSpeed(rate, p.TotalSize-p.TotalDone))) + "\n"
+	}
+	return view
+}
+
+// transferFileLine describes a completed file, e.g. "✓ app/main.js  1.2 MB  sha256, resumed at 300 kB".
+func transferFileLine(p remotessh.TransferProgress) string {
+	detail := "verified " + p.Verified
+	if p.Resumed > 0 {
+		detail += ", resumed at " + humanize.Bytes(uint64(p.Resumed))
+	}
+	return okStyle.Render("✓ ") + p.Path + "  " + transferDimStyle.Render(humanize.Bytes(uint64(p.Size))+"  "+detail)
+}
+
+// transferRate is bytes per second, or 0 until there is enough to measure.
+func transferRate(bytes int64, elapsed time.Duration) float64 {
+	if bytes <= 0 || elapsed < time.Second/2 {
+		return 0
+	}
+	return float64(bytes) / elapsed.Seconds()
+}
+
+// transferSpeed renders " · 5.2 MB/s · ETA 6s" for the remaining bytes at rate.
+// The ETA is left out when nothing is known to remain.
+func transferSpeed(rate float64, remaining int64) string {
+	if rate == 0 {
+		return ""
+	}
+	if remaining <= 0 {
+		return fmt.Sprintf(" · %s/s", humanize.Bytes(uint64(rate)))
+	}
+	eta := time.Duration(float64(remaining) / rate * float64(time.Second))
+	return fmt.Sprintf(" · %s/s · ETA %s", humanize.Bytes(uint64(rate)), eta.Round(time.Second))
+}
+
+func ratio(done, total int64) float64 {
+	if total <= 0 {
+		return 1
+	}
+	return min(float64(done)/float64(total), 1)
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"remotelink/config"
+	"remotelink/models"
+	remotessh "remotelink/ssh"
+	"remotelink/tunnel"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tunnelName    string
+	tunnelDownAll bool
+)
+
+// tunnelUpWait is how long "tunnel up" waits to report whether the first connection succeeded.
+const tunnelUpWait = 5 * time.Second
+
+var tunnelCmd = &cobra.Command{
+	Use:   "tunnel",
+	Short: "Keep port forwards running in the background",
+	Long: `Keep port forwards running in the background.
+
+Tunnels are served by a local daemon that reconnects with backoff when the SSH link drops.
+It is started by "tunnel up" and exits when the last tunnel is brought down.`,
+}
+
+var tunnelUpCmd = &cobra.Command{
+	Use:   "up [server] [preset...]",
+	Short: "Start a background tunnel",
+	Long: `Start a background tunnel. Forwards are given like for "remotelink forward".
+
+  remotelink tunnel up prod db
+  remotelink tunnel up prod --name pg -L 5432:localhost:5432`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var server models.Server
+		var err error
+		if len(args) > 0 {
+			server, err = findServer(args[0])
+		} else {
+			server, err = SelectServer()
+		}
+		if err != nil {
+			return err
+		}
+
+		forwards, err := forwardsFromArgs(server, args)
+		if err != nil {
+			return err
+		}
+		if len(forwards) == 0 {
+			return fmt.Errorf("no forwards given; use -L, -R, -D or a preset name")
+		}
+
+		// 데몬은 호스트 키를 물어볼 수 없으므로 먼저 확인
+		if err := remotessh.TrustHostKey(server); err != nil {
+			return err
+		}
+
+		name := tunnelName
+		if name == "" {
+			name = defaultTunnelName(server, args)
+		}
+
+		status, err := tunnel.Up(name, server.ServerName, remotessh.DefaultBackend, forwards)
+		if err != nil {
+			return fmt.Errorf("❌ %w", err)
+		}
+		config.RecordServerUse(server.ServerName)
+
+		// 첫 연결 결과를 잠시 기다려서 보고
+		deadline := time.Now().Add(tunnelUpWait)
+		for status.State == tunnel.StateConnecting && time.Now().Before(deadline) {
+			time.Sleep(200 * time.Millisecond)
+			current, found, err := tunnel.Get(name)
+			if err != nil || !found {
+				break
+			}
+			status = current
+		}
+
+		switch status.State {
+		case tunnel.StateUp:
+			fmt.Printf("✅ Tunnel '%s' is up on %s\n", name, server.ServerName)
+		case tunnel.StateReconnecting:
+			fmt.Printf("⚠️  Tunnel '%s' could not connect yet: %s\n", name, status.LastError)
+			fmt.Println("   It keeps retrying in the background; see 'remotelink tunnel ls'")
+		default:
+			fmt.Printf("⏳ Tunnel '%s' is still connecting; see 'remotelink tunnel ls'\n", name)
+		}
+		for _, forward := range forwards {
+			fmt.Printf("   %s\n", describePreset(forward))
+		}
+		return nil
+	},
+}
+
+var tunnelDownCmd = &cobra.Command{
+	Use:   "down [name...]",
+	Short: "Stop background tunnels",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names := args
+		if len(names) == 0 && !tunnelDownAll {
+			statuses, err := tunnel.List()
+			if err != nil {
+				return err
+			}
+			if len(statuses) == 0 {
+				fmt.Println("No tunnels running")
+				return nil
+			}
+
+			options := make([]huh.Option[string], len(statuses))
+			for i, status := range statuses {
+				options[i] = huh.NewOption(fmt.Sprintf("%-16s %s (%s)", status.Name, status.Server, status.State), status.Name)
+			}
+			form := huh.NewForm(
+				huh.NewGroup(
+					huh.NewMultiSelect[string]().
+						Title("Select tunnels to stop").
+						Options(options...).
+						Value(&names),
+				),
+			)
+			if err := form.Run(); err != nil {
+				return err
+			}
+		}
+
+		if tunnelDownAll {
+			stopped, err := tunnel.Down("", true)
+			if errors.Is(err, tunnel.ErrDaemonNotRunning) {
+				fmt.Println("No tunnels running")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			for _, status := range stopped {
+				fmt.Printf("✅ Tunnel '%s' stopped\n", status.Name)
+			}
+			return nil
+		}
+
+		for _, name := range names {
+			if _, err := tunnel.Down(name, false); err != nil {
+				return fmt.Errorf("❌ %w", err)
+			}
+			fmt.Printf("✅ Tunnel '%s' stopped\n", name)
+		}
+		return nil
+	},
+}
+
+var tunnelListCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List background tunnels",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		statuses, err := tunnel.List()
+		if err != nil {
+			return err
+		}
+		if len(statuses) == 0 {
+			fmt.Println("No tunnels running")
+			return nil
+		}
+
+		rows := make([][]string, len(statuses))
+		for i, status := range statuses {
+			forwards := make([]string, len(status.Forwards))
+			for j, forward := range status.Forwards {
+				forwards[j] = describePreset(forward)
+			}
+
+			sent, received := humanize.Bytes(uint64(status.Sent)), humanize.Bytes(uint64(status.Received))
+			if status.Backend == remotessh.BackendExec {
+				sent, received = "-", "-"
+			}
+
+			uptime := "-"
+			if d := status.Uptime(); d > 0 {
+				uptime = d.Round(time.Second).String()
+			}
+
+			rows[i] = []string{
+				status.Name,
+				status.Server,
+				tunnelStateStyle(status.State).Render(status.State),
+				strings.Join(forwards, "\n"),
+				sent,
+				received,
+				uptime,
+				fmt.Sprintf("%d", status.Reconnects),
+				firstLine(status.LastError),
+			}
+		}
+
+		t := newTable(-1, []string{"NAME", "SERVER", "STATE", "FORWARDS", "SENT", "RECEIVED", "UPTIME", "RETRIES", "LAST ERROR"}, rows)
+
+		fmt.Println(t.Render())
+		return nil
+	},
+}
+
+var tunnelDaemonCmd = &cobra.Command{
+	Use:    "daemon",
+	Short:  "Run the tunnel daemon in the foreground",
+	Hidden: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return tunnel.RunDaemon()
+	},
+}
+
+// defaultTunnelName names a tunnel after its server and presets, e.g. "prod-db".
+func defaultTunnelName(server models.Server, args []string) string {
+	if len(args) > 1 {
+		return server.ServerName + "-" + strings.Join(args[1:], "-")
+	}
+	return server.ServerName
+}
+
+func tunnelStateStyle(state string) lipgloss.Style {
+	switch state {
+	case tunnel.StateUp:
+		return okStyle
+	case tunnel.StateReconnecting:
+		return failedStyle
+	}
+	return warnStyle
+}
+
+func init() {
+	tunnelUpCmd.Flags().StringVarP(&tunnelName, "name", "n", "", "Tunnel name (default: server and preset names)")
+	tunnelUpCmd.Flags().StringArrayVarP(&forwardLocal, "local", "L", nil, "Local forward [bind:]port:host:hostport")
+	tunnelUpCmd.Flags().StringArrayVarP(&forwardRemote, "remote", "R", nil, "Remote forward [bind:]port:host:hostport")
+	tunnelUpCmd.Flags().StringArrayVarP(&forwardDynamic, "dynamic", "D", nil, "SOCKS5 proxy on [bind:]port")
+	tunnelUpCmd.Flags().StringVarP(&forwardContainer, "container", "c", "", "Container whose IP -L forwards target ([bind:]port:containerport)")
+	addSelectorFlags(tunnelUpCmd)
+	tunnelDownCmd.Flags().BoolVar(&tunnelDownAll, "all", false, "Stop every tunnel")
+
+	tunnelCmd.AddCommand(tunnelUpCmd, tunnelDownCmd, tunnelListCmd, tunnelDaemonCmd)
+	rootCmd.AddCommand(tunnelCmd)
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"remotelink/models"
+	"slices"
+	"sort"
+)
+
+// Groups returns the distinct non-empty groups of the configured servers, sorted.
+func Groups() []string {
+	var groups []string
+	for _, server := range Servers {
+		if server.Group != "" && !slices.Contains(groups, server.Group) {
+			groups = append(groups, server.Group)
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// Tags returns the distinct tags of the given servers, sorted.
+func Tags(servers []models.Server) []string {
+	var tags []string
+	for _, server := range servers {
+		for _, tag := range server.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// FilterServers returns the servers in group that carry every tag in tags.
+// An empty group or tag list matches everything.
+func FilterServers(group string, tags []string) []models.Server {
+	var matched []models.Server
+	for _, server := range Servers {
+		if MatchesSelector(server, group, tags) {
+			matched = append(matched, server)
+		}
+	}
+	return matched
+}
+
+// MatchesSelector reports whether server is in group and carries every tag in tags.
+func MatchesSelector(server models.Server, group string, tags []string) bool {
+	if group != "" && server.Group != group {
+		return false
+	}
+	for _, tag := range tags {
+		if !slices.Contains(server.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// GroupServers splits servers by group, keeping their order within each group.
+// Groups are sorted by name and ungrouped servers come last under "".
+func GroupServers(servers []models.Server) (groups []string, byGroup map[string][]models.Server) {
+	byGroup = map[string][]models.Server{}
+	for _, server := range servers {
+		if _, seen := byGroup[server.Group]; !seen && server.Group != "" {
+			groups = append(groups, server.Group)
+		}
+		byGroup[server.Group] = append(byGroup[server.Group], server)
+	}
+	sort.Strings(groups)
+	if len(byGroup[""]) > 0 {
+		groups = append(groups, "")
+	}
+	return groups, byGroup
+}
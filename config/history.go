@@ -0,0 +1,130 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"remotelink/models"
+	"slices"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// ServerHistory is what remotelink remembers about a server between runs.
+type ServerHistory struct {
+	LastUsed   time.Time `json:"last_used"`
+	Containers []string  `json:"containers,omitempty"`
+	// Compose maps compose project names to where they live, so a project can still be
+	// found after `down` removed the containers whose labels recorded it.
+	Compose map[string]ComposeLocation `json:"compose,omitempty"`
+}
+
+// ComposeLocation is the working directory and compose files of a compose project on a server.
+type ComposeLocation struct {
+	WorkingDir  string   `json:"working_dir"`
+	ConfigFiles []string `json:"config_files,omitempty"`
+}
+
+var history map[string]ServerHistory
+
+func historyPath() string {
+	home, _ := homedir.Dir()
+	return path.Join(home, ".remotelink", "history.json")
+}
+
+// loadHistory reads history.json once. A missing or unreadable file is treated as empty.
+func loadHistory() map[string]ServerHistory {
+	if history != nil {
+		return history
+	}
+	history = map[string]ServerHistory{}
+
+	data, err := os.ReadFile(historyPath())
+	if err != nil {
+		return history
+	}
+	json.Unmarshal(data, &history)
+	return history
+}
+
+// saveHistory writes history.json. History only orders and completes suggestions,
+// so a failed write is reported and otherwise ignored.
+func saveHistory() {
+	if err := writeHistory(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save history: %v\n", err)
+	}
+}
+
+func writeHistory() error {
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Dir(historyPath()), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(historyPath(), data, 0644)
+}
+
+// History returns what is remembered about the named server.
+func History(name string) ServerHistory {
+	return loadHistory()[name]
+}
+
+// RecordServerUse marks the named server as just used.
+func RecordServerUse(name string) {
+	entry := loadHistory()[name]
+	entry.LastUsed = time.Now()
+	history[name] = entry
+	saveHistory()
+}
+
+// RecordContainers remembers the containers last seen running on the named server.
+func RecordContainers(name string, containers []models.Container) {
+	entry := loadHistory()[name]
+	entry.Containers = nil
+	for _, container := range containers {
+		entry.Containers = append(entry.Containers, container.Ref())
+	}
+	history[name] = entry
+	saveHistory()
+}
+
+// RecordComposeProjects remembers where the given compose projects live on the named server.
+// Projects that are not given are kept.
+func RecordComposeProjects(name string, projects map[string]ComposeLocation) {
+	entry := loadHistory()[name]
+	if entry.Compose == nil {
+		entry.Compose = map[string]ComposeLocation{}
+	}
+	for project, location := range projects {
+		entry.Compose[project] = location
+	}
+	history[name] = entry
+	saveHistory()
+}
+
+// KnownContainers returns the references of the containers configured for server or last seen on it.
+func KnownContainers(server models.Server) []string {
+	var names []string
+	for _, container := range server.Containers {
+		names = append(names, container.Ref())
+	}
+	for _, name := range History(server.ServerName).Containers {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ForgetHistory drops everything remembered about the named server.
+func ForgetHistory(name string) {
+	if _, ok := loadHistory()[name]; !ok {
+		return
+	}
+	delete(history, name)
+	saveHistory()
+}
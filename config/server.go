@@ -52,6 +52,73 @@ func LoadServers() {
 	}
 }
 
+// SaveServers writes the current Servers slice back to server.json.
+func SaveServers() error {
+	ServerConfig.Set("servers", Servers)
+	if err := ServerConfig.WriteConfig(); err != nil {
+		return fmt.Errorf("failed to save: %w", err)
+	}
+	return nil
+}
+
+// ReadServers reads the servers from server.json without touching Servers.
+// It is for long-running processes that pick up config changes while other goroutines use their servers.
+func ReadServers() ([]models.Server, error) {
+	v := viper.New()
+	v.SetConfigFile(ServerConfig.ConfigFileUsed())
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	var servers []models.Server
+	if err := v.UnmarshalKey("servers", &servers); err != nil {
+		return nil, err
+	}
+	return servers, nil
+}
+
+// JumpChain returns the jump hosts in front of server, outermost first.
+// Unknown jump names and cycles in the chain are reported as errors.
+func JumpChain(server models.Server) ([]models.Server, error) {
+	return JumpChainIn(Servers, server)
+}
+
+// JumpChainIn is JumpChain resolved against the given server list instead of Servers.
+func JumpChainIn(servers []models.Server, server models.Server) ([]models.Server, error) {
+	var chain []models.Server
+	seen := map[string]bool{server.ServerName: true}
+
+	for name := server.Jump; name != ""; {
+		if seen[name] {
+			return nil, fmt.Errorf("jump chain of '%s' has a cycle at '%s'", server.ServerName, name)
+		}
+		seen[name] = true
+
+		jump, found := findServerIn(servers, name)
+		if !found {
+			return nil, fmt.Errorf("jump server '%s' not found", name)
+		}
+		chain = append([]models.Server{jump}, chain...)
+		name = jump.Jump
+	}
+
+	return chain, nil
+}
+
+// FindServer returns the configured server with the given name.
+func FindServer(name string) (models.Server, bool) {
+	return findServerIn(Servers, name)
+}
+
+func findServerIn(servers []models.Server, name string) (models.Server, bool) {
+	for _, server := range servers {
+		if server.ServerName == name {
+			return server, true
+		}
+	}
+	return models.Server{}, false
+}
+
 func createDefaultConfig() error {
 	// 기본 설정 구조
 	defaultConfig := map[string]interface{}{
@@ -0,0 +1,309 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"remotelink/models"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// maxIncludeDepth limits nested Include directives, as OpenSSH does.
+const maxIncludeDepth = 16
+
+// SSHHost is a concrete host alias found in an OpenSSH config, with its effective settings.
+type SSHHost struct {
+	Alias        string
+	HostName     string
+	Port         int
+	User         string
+	IdentityFile string
+	ProxyJump    string
+}
+
+type sshConfigLine struct {
+	key  string
+	args []string
+}
+
+type sshConfigBlock struct {
+	patterns []string
+	lines    []sshConfigLine
+}
+
+// DefaultSSHConfigPath returns the path of the user's OpenSSH config.
+func DefaultSSHConfigPath() string {
+	home, _ := homedir.Dir()
+	return filepath.Join(home, ".ssh", "config")
+}
+
+// ParseSSHConfig reads an OpenSSH config (following Include) and returns every concrete Host alias
+// with the settings OpenSSH would apply to it. Wildcard-only patterns are not returned as hosts,
+// but their settings are applied to the aliases they match.
+func ParseSSHConfig(configPath string) ([]SSHHost, error) {
+	lines, err := readSSHConfig(configPath, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Host/Match 기준으로 블록 분리 (첫 Host 이전 설정은 모든 호스트에 적용)
+	blocks := []sshConfigBlock{{patterns: []string{"*"}}}
+	var aliases []string
+	seen := map[string]bool{}
+
+	for _, line := range lines {
+		switch line.key {
+		case "host":
+			blocks = append(blocks, sshConfigBlock{patterns: line.args})
+			for _, pattern := range line.args {
+				if strings.ContainsAny(pattern, "*?!") || seen[pattern] {
+					continue
+				}
+				seen[pattern] = true
+				aliases = append(aliases, pattern)
+			}
+		case "match":
+			// Match 블록은 지원하지 않으므로 어떤 호스트에도 적용하지 않음
+			blocks = append(blocks, sshConfigBlock{})
+		default:
+			last := &blocks[len(blocks)-1]
+			last.lines = append(last.lines, line)
+		}
+	}
+
+	hosts := make([]SSHHost, 0, len(aliases))
+	for _, alias := range aliases {
+		hosts = append(hosts, resolveSSHHost(alias, blocks))
+	}
+	return hosts, nil
+}
+
+// resolveSSHHost applies matching blocks in order; the first value obtained for each keyword wins.
+func resolveSSHHost(alias string, blocks []sshConfigBlock) SSHHost {
+	host := SSHHost{Alias: alias}
+
+	for _, block := range blocks {
+		if !matchHostPatterns(alias, block.patterns) {
+			continue
+		}
+		for _, line := range block.lines {
+			if len(line.args) == 0 {
+				continue
+			}
+			value := line.args[0]
+			switch line.key {
+			case "hostname":
+				if host.HostName == "" {
+					host.HostName = strings.ReplaceAll(value, "%h", alias)
+				}
+			case "port":
+				if host.Port == 0 {
+					host.Port, _ = strconv.Atoi(value)
+				}
+			case "user":
+				if host.User == "" {
+					host.User = value
+				}
+			case "identityfile":
+				if host.IdentityFile == "" {
+					host.IdentityFile = value
+				}
+			case "proxyjump":
+				if host.ProxyJump == "" {
+					host.ProxyJump = value
+				}
+			}
+		}
+	}
+
+	if host.HostName == "" {
+		host.HostName = alias
+	}
+	if host.Port == 0 {
+		host.Port = 22
+	}
+	if host.User == "" {
+		host.User = localUsername()
+	}
+	if strings.EqualFold(host.ProxyJump, "none") {
+		host.ProxyJump = ""
+	}
+	return host
+}
+
+// Server maps the host onto a remotelink server. ProxyJump is left to the caller,
+// since it has to be resolved against other server names.
+func (h SSHHost) Server() models.Server {
+	return models.Server{
+		ServerName: h.Alias,
+		HostIp:     h.HostName,
+		Port:       h.Port,
+		Username:   h.User,
+		KeyPath:    h.IdentityFile,
+		Containers: []models.Container{},
+	}
+}
+
+// JumpHosts splits ProxyJump into host aliases, outermost first, dropping user@ and :port parts.
+func (h SSHHost) JumpHosts() []string {
+	if h.ProxyJump == "" {
+		return nil
+	}
+
+	var hops []string
+	for _, hop := range strings.Split(h.ProxyJump, ",") {
+		hop = strings.TrimPrefix(strings.TrimSpace(hop), "ssh://")
+		if i := strings.LastIndex(hop, "@"); i >= 0 {
+			hop = hop[i+1:]
+		}
+		if i := strings.LastIndex(hop, ":"); i >= 0 && !strings.HasSuffix(hop, "]") {
+			hop = hop[:i]
+		}
+		if hop != "" {
+			hops = append(hops, hop)
+		}
+	}
+	return hops
+}
+
+// matchHostPatterns reports whether alias matches a Host line: at least one positive pattern
+// must match and no negated pattern may match.
+func matchHostPatterns(alias string, patterns []string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		if !globMatch(strings.TrimPrefix(pattern, "!"), alias) {
+			continue
+		}
+		if negated {
+			return false
+		}
+		matched = true
+	}
+	return matched
+}
+
+// globMatch implements OpenSSH pattern matching, where * and ? are the only wildcards.
+func globMatch(pattern, s string) bool {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	matched, _ := regexp.MatchString("(?i)^"+expr+"$", s)
+	return matched
+}
+
+// readSSHConfig tokenizes a config file, inlining Include directives at the place they appear.
+func readSSHConfig(configPath string, depth int) ([]sshConfigLine, error) {
+	if depth > maxIncludeDepth {
+		return nil, fmt.Errorf("too many nested Include directives in %s", configPath)
+	}
+
+	f, err := os.Open(configPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []sshConfigLine
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, ok := parseSSHConfigLine(scanner.Text())
+		if !ok {
+			continue
+		}
+
+		if line.key != "include" {
+			lines = append(lines, line)
+			continue
+		}
+
+		for _, pattern := range line.args {
+			included, err := includedFiles(pattern)
+			if err != nil {
+				return nil, err
+			}
+			for _, includedPath := range included {
+				nested, err := readSSHConfig(includedPath, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				lines = append(lines, nested...)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// includedFiles expands an Include argument. Relative paths are resolved against ~/.ssh.
+func includedFiles(pattern string) ([]string, error) {
+	expanded, err := homedir.Expand(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(expanded) {
+		expanded = filepath.Join(filepath.Dir(DefaultSSHConfigPath()), expanded)
+	}
+	return filepath.Glob(expanded)
+}
+
+// parseSSHConfigLine splits "Keyword value" or "Keyword=value", honoring double quotes.
+func parseSSHConfigLine(raw string) (sshConfigLine, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.HasPrefix(raw, "#") {
+		return sshConfigLine{}, false
+	}
+
+	end := strings.IndexAny(raw, " \t=")
+	if end < 0 {
+		return sshConfigLine{key: strings.ToLower(raw)}, true
+	}
+
+	key := strings.ToLower(raw[:end])
+	rest := strings.TrimLeft(raw[end:], " \t")
+	rest = strings.TrimLeft(strings.TrimPrefix(rest, "="), " \t")
+
+	var args []string
+	var current strings.Builder
+	inQuotes := false
+	for _, r := range rest {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case (r == ' ' || r == '\t') && !inQuotes:
+			if current.Len() > 0 {
+				args = append(args, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		args = append(args, current.String())
+	}
+
+	return sshConfigLine{key: key, args: args}, true
+}
+
+func localUsername() string {
+	current, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	// Windows는 DOMAIN\user 형식
+	name := current.Username
+	if i := strings.LastIndex(name, `\`); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseSSHConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   []SSHHost
+	}{
+		{
+			name: "single host",
+			config: `Host web
+  HostName 10.0.0.1
+  Port 2222
+  User deploy
+  IdentityFile ~/.ssh/web`,
+			want: []SSHHost{{Alias: "web", HostName: "10.0.0.1", Port: 2222, User: "deploy", IdentityFile: "~/.ssh/web"}},
+		},
+		{
+			name: "defaults",
+			config: `Host db
+  User root`,
+			want: []SSHHost{{Alias: "db", HostName: "db", Port: 22, User: "root"}},
+		},
+		{
+			name: "first value wins",
+			config: `Host web
+  User deploy
+Host *
+  User root
+  Port 2200`,
+			want: []SSHHost{{Alias: "web", HostName: "web", Port: 2200, User: "deploy"}},
+		},
+		{
+			name: "settings before the first host apply to all",
+			config: `User admin
+Host a b
+  HostName %h.example.com`,
+			want: []SSHHost{
+				{Alias: "a", HostName: "a.example.com", Port: 22, User: "admin"},
+				{Alias: "b", HostName: "b.example.com", Port: 22, User: "admin"},
+			},
+		},
+		{
+			name: "wildcard and negated patterns",
+			config: `Host prod-* !prod-db
+  User ops
+Host prod-web prod-db
+  User dev
+Host *`,
+			want: []SSHHost{
+				{Alias: "prod-web", HostName: "prod-web", Port: 22, User: "ops"},
+				{Alias: "prod-db", HostName: "prod-db", Port: 22, User: "dev"},
+			},
+		},
+		{
+			name: "equals and quotes",
+			config: `Host=web
+  HostName="10.0.0.2"
+  User = deploy
+  IdentityFile "~/my keys/web"
+  # comment`,
+			want: []SSHHost{{Alias: "web", HostName: "10.0.0.2", Port: 22, User: "deploy", IdentityFile: "~/my keys/web"}},
+		},
+		{
+			name: "match blocks are ignored",
+			config: `Host web
+  User deploy
+Match host web
+  Port 2222`,
+			want: []SSHHost{{Alias: "web", HostName: "web", Port: 22, User: "deploy"}},
+		},
+		{
+			name: "proxyjump none",
+			config: `Host web
+  User deploy
+  ProxyJump none
+Host *
+  ProxyJump bastion`,
+			want: []SSHHost{{Alias: "web", HostName: "web", Port: 22, User: "deploy"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config")
+			if err := os.WriteFile(path, []byte(tt.config), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ParseSSHConfig(path)
+			if err != nil {
+				t.Fatalf("ParseSSHConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSSHConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSSHConfigInclude(t *testing.T) {
+	dir := t.TempDir()
+	included := filepath.Join(dir, "included")
+	if err := os.WriteFile(included, []byte("Host db\n  User root\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config")
+	if err := os.WriteFile(path, []byte("Include "+included+"\nHost web\n  User deploy\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ParseSSHConfig(path)
+	if err != nil {
+		t.Fatalf("ParseSSHConfig() error = %v", err)
+	}
+	want := []SSHHost{
+		{Alias: "db", HostName: "db", Port: 22, User: "root"},
+		{Alias: "web", HostName: "web", Port: 22, User: "deploy"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSSHConfig() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{"web", "web", true},
+		{"web", "web2", false},
+		{"WEB", "web", true},
+		{"*", "anything", true},
+		{"prod-*", "prod-web", true},
+		{"prod-*", "staging-web", false},
+		{"web?", "web1", true},
+		{"web?", "web", false},
+		{"*.example.com", "a.example.com", true},
+		{"*.example.com", "aexample.com", false},
+		{"10.0.0.[1]", "10.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.s, func(t *testing.T) {
+			if got := globMatch(tt.pattern, tt.s); got != tt.want {
+				t.Errorf("globMatch(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJumpHosts(t *testing.T) {
+	tests := []struct {
+		proxyJump string
+		want      []string
+	}{
+		{"", nil},
+		{"bastion", []string{"bastion"}},
+		{"user@bastion:2222", []string{"bastion"}},
+		{"ssh://user@bastion:2222", []string{"bastion"}},
+		{"outer, inner", []string{"outer", "inner"}},
+		{"a@outer,b@inner:22", []string{"outer", "inner"}},
+		{"[::1]", []string{"[::1]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.proxyJump, func(t *testing.T) {
+			got := SSHHost{ProxyJump: tt.proxyJump}.JumpHosts()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("JumpHosts() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,16 +1,66 @@
 package models
 
 type Server struct {
-	ServerName  string      `mapstructure:"server_name" json:"server_name"`
-	HostIp      string      `mapstructure:"host_ip" json:"host_ip"`
-	Port        int         `mapstructure:"port" json:"port"`
-	Username    string      `mapstructure:"username" json:"username"`
-	KeyPath     string      `mapstructure:"key_path" json:"key_path"`
-	DefaultPath string      `mapstructure:"default_path" json:"default_path"`
-	Containers  []Container `mapstructure:"containers" json:"containers"`
+	ServerName       string      `mapstructure:"server_name" json:"server_name"`
+	HostIp           string      `mapstructure:"host_ip" json:"host_ip"`
+	Port             int         `mapstructure:"port" json:"port"`
+	Username         string      `mapstructure:"username" json:"username"`
+	KeyPath          string      `mapstructure:"key_path" json:"key_path"`
+	DefaultPath      string      `mapstructure:"default_path" json:"default_path"`
+	SSHBackend       string      `mapstructure:"ssh_backend" json:"ssh_backend,omitempty"`
+	HostKeyPolicy    string      `mapstructure:"host_key_policy" json:"host_key_policy,omitempty"`
+	Transfer         string      `mapstructure:"transfer" json:"transfer,omitempty"`
+	ContainerRuntime string      `mapstructure:"container_runtime" json:"container_runtime,omitempty"`
+	Kubeconfig       string      `mapstructure:"kubeconfig" json:"kubeconfig,omitempty"`
+	Jump             string      `mapstructure:"jump" json:"jump,omitempty"`
+	Group            string      `mapstructure:"group" json:"group,omitempty"`
+	Tags             []string    `mapstructure:"tags" json:"tags,omitempty"`
+	Forwards         []Forward   `mapstructure:"forwards" json:"forwards,omitempty"`
+	Containers       []Container `mapstructure:"containers" json:"containers"`
 }
 
 type Container struct {
 	ContainerName string `mapstructure:"container_name" json:"container_name"`
 	ImageName     string `mapstructure:"image_name" json:"image_name"`
+	// Project and Service come from the docker compose labels, empty for standalone containers.
+	Project string `mapstructure:"project" json:"project,omitempty"`
+	Service string `mapstructure:"service" json:"service,omitempty"`
+	// Namespace and Pod are set for a container in a Kubernetes pod.
+	Namespace string `mapstructure:"namespace" json:"namespace,omitempty"`
+	Pod       string `mapstructure:"pod" json:"pod,omitempty"`
+	// The remaining fields are only known from a live fetch of runtime containers.
+	ID     string `mapstructure:"id" json:"id,omitempty"`
+	State  string `mapstructure:"state" json:"state,omitempty"`
+	Status string `mapstructure:"status" json:"status,omitempty"`
+	// Uptime is how long a running container has been up, e.g. "3 hours".
+	Uptime string `mapstructure:"uptime" json:"uptime,omitempty"`
+	// Health is "healthy", "unhealthy" or "starting", empty without a health check.
+	Health string `mapstructure:"health" json:"health,omitempty"`
+	// Ports are the published ports, e.g. "8080->80/tcp".
+	Ports        []string `mapstructure:"ports" json:"ports,omitempty"`
+	RestartCount int      `mapstructure:"restart_count" json:"restart_count,omitempty"`
+}
+
+// PodRefPrefix starts the reference of a pod container, as in "server/pod/<namespace>/<pod>/<container>".
+// Docker container names cannot contain a slash, so the two never collide.
+const PodRefPrefix = "pod/"
+
+// Ref is how the container is addressed after "server/": its name, or
+// pod/<namespace>/<pod>/<container> for a container in a Kubernetes pod.
+func (c Container) Ref() string {
+	if c.Pod == "" {
+		return c.ContainerName
+	}
+	return PodRefPrefix + c.Namespace + "/" + c.Pod + "/" + c.ContainerName
+}
+
+// Forward is a port forwarding preset. Local is an address on this machine and Remote
+// an address on the server side; which one listens depends on Type (local, remote or dynamic).
+// With Container set, Remote is a port on that container, reached through its IP address.
+type Forward struct {
+	Name      string `mapstructure:"name" json:"name"`
+	Type      string `mapstructure:"type" json:"type"`
+	Local     string `mapstructure:"local" json:"local"`
+	Remote    string `mapstructure:"remote" json:"remote,omitempty"`
+	Container string `mapstructure:"container" json:"container,omitempty"`
 }
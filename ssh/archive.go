@@ -0,0 +1,234 @@
+package ssh
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// writeTar streams srcPath (a file or directory) as a tar archive whose top-level entry is named name.
+func writeTar(w io.Writer, srcPath, name string) error {
+	tw := tar.NewWriter(w)
+	if err := addTarTree(tw, srcPath, name, nil); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// writeTarFiles streams the given paths below root, keeping their relative names.
+// Directories are added with everything below them that skip does not exclude.
+func writeTarFiles(w io.Writer, root string, rels []string, skip func(rel string, isDir bool) bool) error {
+	tw := tar.NewWriter(w)
+	for _, rel := range rels {
+		if err := addTarTree(tw, filepath.Join(root, filepath.FromSlash(rel)), rel, skip); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// addTarTree adds srcPath and, for a directory, everything below it as entries under name.
+func addTarTree(tw *tar.Writer, srcPath, name string, skip func(rel string, isDir bool) bool) error {
+	return filepath.Walk(srcPath, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(srcPath, p)
+		if err != nil {
+			return err
+		}
+		entryName := path.Join(name, filepath.ToSlash(rel))
+
+		if skip != nil && skip(entryName, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = entryName
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
+// extractTar unpacks a tar stream into destDir, renaming the top-level entry to name.
+// Entries that would escape destDir, directly or through a link, are rejected.
+func extractTar(r io.Reader, destDir, name string) error {
+	tr := tar.NewReader(r)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			// 쓰는 쪽이 막히지 않도록 남은 블록 패딩까지 읽기
+			_, err = io.Copy(io.Discard, r)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := archiveTarget(destDir, name, header.Name)
+		if err != nil {
+			return err
+		}
+		// 앞선 항목이 만든 심볼릭 링크를 통해 destDir 밖에 쓰지 않도록 확인
+		if err := checkNoSymlinks(destDir, filepath.Dir(target)); err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if info, err := os.Lstat(target); err == nil && info.Mode()&os.ModeSymlink != 0 {
+				return fmt.Errorf("refusing to write through symlink in archive: %s", header.Name)
+			}
+			if err := os.MkdirAll(target, os.FileMode(header.Mode)|0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			// 기존 링크를 따라가지 않도록 링크 자체를 새 파일로 교체
+			if info, err := os.Lstat(target); err == nil && info.Mode()&os.ModeSymlink != 0 {
+				if err := os.Remove(target); err != nil {
+					return err
+				}
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if !linkInside(destDir, filepath.Dir(target), header.Linkname) {
+				return fmt.Errorf("refusing symlink that points outside the destination: %s -> %s", header.Name, header.Linkname)
+			}
+			os.Remove(target)
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			// 하드 링크 대상도 아카이브 안의 경로
+			source, err := archiveTarget(destDir, name, header.Linkname)
+			if err != nil {
+				return err
+			}
+			if err := checkNoSymlinks(destDir, source); err != nil {
+				return err
+			}
+			os.Remove(target)
+			if err := os.Link(source, target); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// archiveTarget maps an archive entry name to its path below destDir, with the top-level entry
+// renamed to name. Names that would escape destDir are rejected.
+func archiveTarget(destDir, name, entry string) (string, error) {
+	rel := path.Clean(entry)
+	if rel == ".." || strings.HasPrefix(rel, "../") || path.IsAbs(rel) {
+		return "", fmt.Errorf("refusing unsafe path in archive: %s", entry)
+	}
+
+	// 최상위 항목 이름을 목적지 이름으로 교체
+	parts := strings.SplitN(rel, "/", 2)
+	parts[0] = name
+	return filepath.Join(destDir, filepath.FromSlash(strings.Join(parts, "/"))), nil
+}
+
+// checkNoSymlinks fails when any existing component of p below destDir, p included, is a symlink.
+func checkNoSymlinks(destDir, p string) error {
+	rel, err := filepath.Rel(destDir, p)
+	if err != nil || rel == "." {
+		return err
+	}
+	current := destDir
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("refusing to write through symlink in archive: %s", current)
+		}
+	}
+	return nil
+}
+
+// linkInside reports whether a relative symlink in dir pointing at linkname stays in destDir,
+// both as written and, where it exists, once earlier symlinks are resolved.
+func linkInside(destDir, dir, linkname string) bool {
+	if filepath.IsAbs(linkname) {
+		return false
+	}
+	if !within(destDir, filepath.Join(dir, filepath.FromSlash(linkname))) {
+		return false
+	}
+	// "link/.."처럼 앞서 만든 링크를 거치면 경로만으로는 판단할 수 없으므로 정리하지 않은 경로로 해석
+	resolved, err := filepath.EvalSymlinks(dir + string(filepath.Separator) + filepath.FromSlash(linkname))
+	if err != nil {
+		return true
+	}
+	realDest, err := filepath.EvalSymlinks(destDir)
+	return err == nil && within(realDest, resolved)
+}
+
+// within reports whether p is destDir or below it, lexically.
+func within(destDir, p string) bool {
+	rel, err := filepath.Rel(destDir, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// localDestination resolves where a transferred entry lands, following scp semantics:
+// into localPath when it is an existing directory, otherwise as localPath itself.
+func localDestination(localPath, srcName string) (dir, name string) {
+	if info, err := os.Stat(localPath); err == nil && info.IsDir() {
+		return localPath, srcName
+	}
+	return filepath.Dir(localPath), filepath.Base(localPath)
+}
@@ -0,0 +1,171 @@
+package ssh
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type tarEntry struct {
+	name     string
+	typeflag byte
+	linkname string
+	body     string
+}
+
+func buildTar(t *testing.T, entries []tarEntry) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Typeflag: e.typeflag, Linkname: e.linkname, Mode: 0644, Size: int64(len(e.body))}
+		if e.typeflag == tar.TypeDir {
+			header.Mode = 0755
+		}
+		if e.typeflag != tar.TypeReg {
+			header.Size = 0
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if e.typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(e.body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestExtractTar(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []tarEntry
+		wantErr string
+		// files maps paths below the destination to their expected contents.
+		files map[string]string
+	}{
+		{
+			name: "regular tree",
+			entries: []tarEntry{
+				{name: "src/", typeflag: tar.TypeDir},
+				{name: "src/a.txt", typeflag: tar.TypeReg, body: "a"},
+				{name: "src/sub/b.txt", typeflag: tar.TypeReg, body: "b"},
+			},
+			files: map[string]string{"out/a.txt": "a", "out/sub/b.txt": "b"},
+		},
+		{
+			name: "internal symlink and hardlink",
+			entries: []tarEntry{
+				{name: "src/", typeflag: tar.TypeDir},
+				{name: "src/a.txt", typeflag: tar.TypeReg, body: "a"},
+				{name: "src/link", typeflag: tar.TypeSymlink, linkname: "a.txt"},
+				{name: "src/hard", typeflag: tar.TypeLink, linkname: "src/a.txt"},
+			},
+			files: map[string]string{"out/link": "a", "out/hard": "a"},
+		},
+		{
+			name:    "parent path",
+			entries: []tarEntry{{name: "../evil", typeflag: tar.TypeReg, body: "x"}},
+			wantErr: "unsafe path",
+		},
+		{
+			name:    "absolute path",
+			entries: []tarEntry{{name: "/tmp/evil", typeflag: tar.TypeReg, body: "x"}},
+			wantErr: "unsafe path",
+		},
+		{
+			name: "symlink outside then write through it",
+			entries: []tarEntry{
+				{name: "src/", typeflag: tar.TypeDir},
+				{name: "src/link", typeflag: tar.TypeSymlink, linkname: "../../outside"},
+				{name: "src/link/evil", typeflag: tar.TypeReg, body: "x"},
+			},
+			wantErr: "points outside",
+		},
+		{
+			name: "absolute symlink",
+			entries: []tarEntry{
+				{name: "src/", typeflag: tar.TypeDir},
+				{name: "src/link", typeflag: tar.TypeSymlink, linkname: "/etc"},
+			},
+			wantErr: "points outside",
+		},
+		{
+			name: "symlink escaping through an earlier symlink",
+			entries: []tarEntry{
+				{name: "src/", typeflag: tar.TypeDir},
+				{name: "src/self", typeflag: tar.TypeSymlink, linkname: "."},
+				{name: "src/up", typeflag: tar.TypeSymlink, linkname: "self/../.."},
+			},
+			wantErr: "points outside",
+		},
+		{
+			name: "write through internal symlink",
+			entries: []tarEntry{
+				{name: "src/", typeflag: tar.TypeDir},
+				{name: "src/dir/", typeflag: tar.TypeDir},
+				{name: "src/link", typeflag: tar.TypeSymlink, linkname: "dir"},
+				{name: "src/link/file", typeflag: tar.TypeReg, body: "x"},
+			},
+			wantErr: "through symlink",
+		},
+		{
+			name: "regular file replaces symlink instead of following it",
+			entries: []tarEntry{
+				{name: "src/", typeflag: tar.TypeDir},
+				{name: "src/a.txt", typeflag: tar.TypeReg, body: "a"},
+				{name: "src/link", typeflag: tar.TypeSymlink, linkname: "a.txt"},
+				{name: "src/link", typeflag: tar.TypeReg, body: "new"},
+			},
+			files: map[string]string{"out/a.txt": "a", "out/link": "new"},
+		},
+		{
+			name: "hardlink outside",
+			entries: []tarEntry{
+				{name: "src/", typeflag: tar.TypeDir},
+				{name: "src/hard", typeflag: tar.TypeLink, linkname: "../outside"},
+			},
+			wantErr: "unsafe path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			destDir := filepath.Join(root, "dest")
+			if err := os.Mkdir(destDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			err := extractTar(buildTar(t, tt.entries), destDir, "out")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("extractTar() error = %v, want %q", err, tt.wantErr)
+				}
+				if _, err := os.Lstat(filepath.Join(root, "outside")); !os.IsNotExist(err) {
+					t.Fatalf("file created outside the destination")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("extractTar() error = %v", err)
+			}
+			for rel, want := range tt.files {
+				got, err := os.ReadFile(filepath.Join(destDir, rel))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != want {
+					t.Errorf("%s = %q, want %q", rel, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"remotelink/models"
+)
+
+// Backend names accepted by the ssh_backend server field and the --backend flag.
+const (
+	BackendNative = "native"
+	BackendExec   = "exec"
+)
+
+// DefaultBackend is used for servers that do not set ssh_backend.
+var DefaultBackend = BackendNative
+
+// Backend is the transport used to reach a server.
+// The native backend runs SSH in-process; the exec backend shells out to the ssh and scp binaries.
+type Backend interface {
+	// Run executes a command and returns its trimmed combined output.
+	Run(server models.Server, command string) (string, error)
+	// Interactive attaches the local terminal to a remote command, or a login shell when command is empty.
+	Interactive(server models.Server, command string) error
+	// Upload copies a local file or directory to the server.
+	Upload(server models.Server, localPath, remotePath string) error
+	// Download copies a remote file or directory to the local machine.
+	Download(server models.Server, remotePath, localPath string) error
+	// Stream runs a command with its standard streams connected to stdin, stdout and stderr; any may be nil.
+	// When stderr is nil, the command's stderr is included in the returned error instead.
+	Stream(server models.Server, command string, stdin io.Reader, stdout, stderr io.Writer) error
+	// Forward serves resolved port forwards until ctx is cancelled or the connection drops.
+	Forward(ctx context.Context, server models.Server, forwards []models.Forward, opts ForwardOptions) error
+}
+
+// ValidateBackend reports whether name is a known backend.
+func ValidateBackend(name string) error {
+	switch name {
+	case BackendNative, BackendExec:
+		return nil
+	}
+	return fmt.Errorf("unknown ssh backend %q (expected %q or %q)", name, BackendNative, BackendExec)
+}
+
+// BackendFor returns the backend configured for the server, falling back to DefaultBackend.
+func BackendFor(server models.Server) Backend {
+	name := server.SSHBackend
+	if name == "" {
+		name = DefaultBackend
+	}
+	if name == BackendExec {
+		return execBackend{}
+	}
+	return nativeBackend{}
+}
+
+// Interactive attaches the local terminal to a remote command on the server.
+func Interactive(server models.Server, command string) error {
+	return BackendFor(server).Interactive(server, command)
+}
@@ -0,0 +1,225 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"remotelink/models"
+	"strconv"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+const (
+	connectTimeout   = 5 * time.Second
+	handshakeTimeout = 10 * time.Second
+)
+
+// defaultKeyFiles are tried in order when a server has no key_path, like OpenSSH does.
+var defaultKeyFiles = []string{"id_ed25519", "id_ecdsa", "id_rsa"}
+
+// ResolveJumps returns the jump hosts in front of a server, outermost first.
+// It is set by the config package so jump names can be resolved against config.Servers.
+var ResolveJumps func(server models.Server) ([]models.Server, error)
+
+// Client is a native SSH connection that also owns the jump host connections it was tunnelled through.
+type Client struct {
+	*gossh.Client
+	jumps []*gossh.Client
+}
+
+// Close closes the connection and then every jump host connection, innermost first.
+func (c *Client) Close() error {
+	err := c.Client.Close()
+	for i := len(c.jumps) - 1; i >= 0; i-- {
+		c.jumps[i].Close()
+	}
+	return err
+}
+
+// Dial opens an in-process SSH connection to the server, tunnelling through its jump hosts if any.
+// Authentication uses the configured key file and the running ssh-agent, if any.
+func Dial(server models.Server) (*Client, error) {
+	conn, jumps, err := dialConn(server)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := handshake(conn, server)
+	if err != nil {
+		closeAll(jumps)
+		return nil, err
+	}
+
+	return &Client{Client: client, jumps: jumps}, nil
+}
+
+// dialConn opens a raw connection to the server's SSH port, either directly or through its jump chain.
+// The returned jump clients must be closed by the caller once the connection is no longer needed.
+func dialConn(server models.Server) (net.Conn, []*gossh.Client, error) {
+	hops, err := jumpHops(server)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var jumps []*gossh.Client
+	for _, hop := range hops {
+		conn, err := dialVia(jumps, hop)
+		if err != nil {
+			closeAll(jumps)
+			return nil, nil, fmt.Errorf("jump host '%s': %w", hop.ServerName, err)
+		}
+		client, err := handshake(conn, hop)
+		if err != nil {
+			closeAll(jumps)
+			return nil, nil, fmt.Errorf("jump host '%s': %w", hop.ServerName, err)
+		}
+		jumps = append(jumps, client)
+	}
+
+	conn, err := dialVia(jumps, server)
+	if err != nil {
+		closeAll(jumps)
+		return nil, nil, err
+	}
+	return conn, jumps, nil
+}
+
+// dialVia opens a TCP connection to the server, from the innermost jump host when there is one.
+func dialVia(jumps []*gossh.Client, server models.Server) (net.Conn, error) {
+	addr := serverAddr(server)
+
+	var conn net.Conn
+	var err error
+	if len(jumps) == 0 {
+		conn, err = net.DialTimeout("tcp", addr, connectTimeout)
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+		conn, err = jumps[len(jumps)-1].DialContext(ctx, "tcp", addr)
+		cancel()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrHostUnreachable, addr, err)
+	}
+	return conn, nil
+}
+
+// jumpHops resolves the server's jump chain, outermost first.
+func jumpHops(server models.Server) ([]models.Server, error) {
+	if server.Jump == "" {
+		return nil, nil
+	}
+	if ResolveJumps == nil {
+		return nil, fmt.Errorf("cannot resolve jump host '%s'", server.Jump)
+	}
+	return ResolveJumps(server)
+}
+
+// handshake authenticates over conn. The connection is closed on failure.
+func handshake(conn net.Conn, server models.Server) (*gossh.Client, error) {
+	config, err := clientConfig(server)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// 핸드셰이크가 멈추지 않도록 시간 초과 시 연결 종료
+	addr := serverAddr(server)
+	timer := time.AfterFunc(handshakeTimeout, func() { conn.Close() })
+	c, chans, reqs, err := gossh.NewClientConn(conn, addr, config)
+	timer.Stop()
+	if err != nil {
+		conn.Close()
+		return nil, classifyHandshakeError(addr, err)
+	}
+
+	return gossh.NewClient(c, chans, reqs), nil
+}
+
+func closeAll(clients []*gossh.Client) {
+	for i := len(clients) - 1; i >= 0; i-- {
+		clients[i].Close()
+	}
+}
+
+func serverAddr(server models.Server) string {
+	port := server.Port
+	if port == 0 {
+		port = 22
+	}
+	return net.JoinHostPort(server.HostIp, strconv.Itoa(port))
+}
+
+func clientConfig(server models.Server) (*gossh.ClientConfig, error) {
+	signers, err := loadSigners(server)
+	if err != nil {
+		return nil, err
+	}
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("%w: no usable SSH key or agent for %s", ErrAuthFailed, server.ServerName)
+	}
+
+	return &gossh.ClientConfig{
+		User:            server.Username,
+		Auth:            []gossh.AuthMethod{gossh.PublicKeys(signers...)},
+		HostKeyCallback: hostKeyCallback(server),
+		Timeout:         connectTimeout,
+	}, nil
+}
+
+// loadSigners collects signers from the server's key file (or the default keys) and ssh-agent.
+func loadSigners(server models.Server) ([]gossh.Signer, error) {
+	var signers []gossh.Signer
+
+	if server.KeyPath != "" {
+		signer, err := readKeyFile(server.KeyPath)
+		if err != nil {
+			var passErr *gossh.PassphraseMissingError
+			if !errors.As(err, &passErr) {
+				return nil, err
+			}
+			// 암호가 걸린 키는 agent에 등록된 것으로 간주
+		} else {
+			signers = append(signers, signer)
+		}
+	} else {
+		home, _ := homedir.Dir()
+		for _, name := range defaultKeyFiles {
+			if signer, err := readKeyFile(filepath.Join(home, ".ssh", name)); err == nil {
+				signers = append(signers, signer)
+			}
+		}
+	}
+
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			if agentSigners, err := agent.NewClient(conn).Signers(); err == nil {
+				signers = append(signers, agentSigners...)
+			}
+		}
+	}
+
+	return signers, nil
+}
+
+func readKeyFile(keyPath string) (gossh.Signer, error) {
+	expanded, err := homedir.Expand(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(expanded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key %s: %w", keyPath, err)
+	}
+	signer, err := gossh.ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key %s: %w", keyPath, err)
+	}
+	return signer, nil
+}
@@ -0,0 +1,157 @@
+package ssh
+
+import (
+	"fmt"
+	"remotelink/models"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Labels docker compose puts on the containers it creates.
+const (
+	composeProjectLabel     = "com.docker.compose.project"
+	composeServiceLabel     = "com.docker.compose.service"
+	composeWorkingDirLabel  = "com.docker.compose.project.working_dir"
+	composeConfigFilesLabel = "com.docker.compose.project.config_files"
+)
+
+// Compose actions accepted by ComposeCommand.
+const (
+	ComposePs   = "ps"
+	ComposeUp   = "up"
+	ComposeDown = "down"
+	ComposePull = "pull"
+	ComposeLogs = "logs"
+)
+
+// ComposeActions lists the compose actions in the order they are documented.
+var ComposeActions = []string{ComposePs, ComposeUp, ComposeDown, ComposePull, ComposeLogs}
+
+// ComposeProject is a compose stack on a server, as recovered from its containers' labels.
+type ComposeProject struct {
+	Name       string
+	WorkingDir string
+	// ConfigFiles are the compose files the project was started with, absolute on the server.
+	ConfigFiles []string
+	Services    []string
+	Running     int
+	Total       int
+}
+
+// ComposeProjects returns the compose projects that have containers on the server, stopped ones
+// included, sorted by name.
+func ComposeProjects(server models.Server) ([]ComposeProject, error) {
+	format := strings.Join([]string{
+		labelFormat(composeProjectLabel),
+		labelFormat(composeServiceLabel),
+		labelFormat(composeWorkingDirLabel),
+		labelFormat(composeConfigFilesLabel),
+		"{{.Status}}",
+	}, "\t")
+	output, err := ExecuteRemoteCommand(server, runtimeCommand(server, fmt.Sprintf(`"$rt" ps -a --filter label=%s --format '%s'`,
+		composeProjectLabel, format)))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not installed on the remote server: %w", runtimeName(server), err)
+	}
+
+	byName := map[string]*ComposeProject{}
+	var names []string
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(strings.TrimSpace(line), "\t")
+		if len(parts) != 5 || parts[0] == "" {
+			continue
+		}
+
+		project, ok := byName[parts[0]]
+		if !ok {
+			project = &ComposeProject{Name: parts[0], WorkingDir: parts[2]}
+			if parts[3] != "" {
+				project.ConfigFiles = strings.Split(parts[3], ",")
+			}
+			byName[parts[0]] = project
+			names = append(names, parts[0])
+		}
+		if !slices.Contains(project.Services, parts[1]) {
+			project.Services = append(project.Services, parts[1])
+		}
+		project.Total++
+		if containerState(parts[4]) == StateRunning {
+			project.Running++
+		}
+	}
+
+	sort.Strings(names)
+	projects := make([]ComposeProject, len(names))
+	for i, name := range names {
+		projects[i] = *byName[name]
+		sort.Strings(projects[i].Services)
+	}
+	return projects, nil
+}
+
+// ValidateComposeAction reports whether action is a supported compose action.
+func ValidateComposeAction(action string) error {
+	if !slices.Contains(ComposeActions, action) {
+		return fmt.Errorf("unknown compose action %q (expected one of %s)", action, strings.Join(ComposeActions, ", "))
+	}
+	return nil
+}
+
+// ComposeCommand builds the remote command that runs a compose action for the project from its
+// working directory with its original compose files. args are appended to the action; up runs
+// detached. Both the runtime's compose plugin and the standalone docker-compose, podman-compose
+// or nerdctl-compose are supported.
+func ComposeCommand(server models.Server, project ComposeProject, action string, args []string) (string, error) {
+	if err := ValidateComposeAction(action); err != nil {
+		return "", err
+	}
+	if project.WorkingDir == "" {
+		return "", fmt.Errorf("compose project '%s' has no working directory label", project.Name)
+	}
+
+	// $compose는 "docker compose"처럼 두 단어일 수 있어 따옴표 없이 사용
+	compose := []string{"$compose", "-p", shellQuote(project.Name)}
+	for _, file := range project.ConfigFiles {
+		compose = append(compose, "-f", shellQuote(file))
+	}
+	compose = append(compose, action)
+	if action == ComposeUp {
+		compose = append(compose, "-d")
+	}
+	for _, arg := range args {
+		compose = append(compose, shellQuote(arg))
+	}
+
+	// compose 플러그인이 없으면 docker-compose 같은 별도 실행 파일 사용
+	return runtimeCommand(server,
+		`if "$rt" compose version >/dev/null 2>&1; then compose="$rt compose"; else compose="${rt##*/}-compose"; fi`,
+		"cd "+shellQuote(project.WorkingDir),
+		strings.Join(compose, " ")), nil
+}
+
+// GroupContainers splits containers by compose project, sorted by name, with containers that
+// belong to no project under "" last.
+func GroupContainers(containers []models.Container) (projects []string, byProject map[string][]models.Container) {
+	byProject = map[string][]models.Container{}
+	for _, container := range containers {
+		if _, seen := byProject[container.Project]; !seen && container.Project != "" {
+			projects = append(projects, container.Project)
+		}
+		byProject[container.Project] = append(byProject[container.Project], container)
+	}
+	sort.Strings(projects)
+	for _, project := range projects {
+		sort.SliceStable(byProject[project], func(i, j int) bool {
+			return byProject[project][i].Service < byProject[project][j].Service
+		})
+	}
+	if len(byProject[""]) > 0 {
+		projects = append(projects, "")
+	}
+	return projects, byProject
+}
+
+func labelFormat(label string) string {
+	return fmt.Sprintf(`{{.Label "%s"}}`, label)
+}
@@ -0,0 +1,168 @@
+package ssh
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"remotelink/models"
+	"strconv"
+	"strings"
+)
+
+// Endpoint is one side of a server-to-server copy: a path on a server, or inside a container on it.
+type Endpoint struct {
+	Server    models.Server
+	Container string
+	Path      string
+}
+
+// Copy streams a file or directory from src to dst through this machine, so neither server
+// needs to reach the other. It follows scp semantics: an existing directory receives the source
+// by name, anything else is the destination itself. Progress is reported through opts.Progress
+// against the source's disk usage, which is an estimate. It returns the number of file bytes copied.
+func Copy(ctx context.Context, src, dst Endpoint, opts TransferOptions) (int64, error) {
+	srcPath := path.Clean(src.Path)
+	srcName := path.Base(srcPath)
+
+	total := remoteSize(src, srcPath)
+
+	destDir, name, err := endpointDestination(dst, srcName)
+	if err != nil {
+		return 0, err
+	}
+
+	var readCmd string
+	if src.Container != "" {
+		t := targetFor(src.Server, src.Container)
+		readCmd = t.command(t.copyOut(srcPath))
+	} else {
+		readCmd = fmt.Sprintf("tar -cf - -C %s %s", remoteShellPath(path.Dir(srcPath)), shellQuote(srcName))
+	}
+	var writeCmd string
+	if dst.Container != "" {
+		t := targetFor(dst.Server, dst.Container)
+		writeCmd = t.command(t.copyIn(destDir))
+	} else {
+		dir := remoteShellPath(destDir)
+		writeCmd = fmt.Sprintf("mkdir -p %s && tar -xf - -C %s", dir, dir)
+	}
+
+	srcR, srcW := io.Pipe()
+	dstR, dstW := io.Pipe()
+
+	// 한쪽이 실패하거나 취소되면 양쪽 원격 tar를 모두 종료해 상대가 막히지 않도록 함
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	readDone := make(chan error, 1)
+	go func() {
+		err := streamCommand(streamCtx, src.Server, readCmd, nil, srcW, nil)
+		srcW.CloseWithError(err)
+		readDone <- err
+	}()
+
+	writeDone := make(chan error, 1)
+	go func() {
+		err := streamCommand(streamCtx, dst.Server, writeCmd, dstR, nil, nil)
+		// 받는 쪽이 먼저 실패하면 보내는 쪽 읽기도 멈춤
+		dstR.CloseWithError(err)
+		if err != nil {
+			cancel()
+		}
+		writeDone <- err
+	}()
+
+	t := &sftpTransfer{ctx: ctx, opts: opts, totalSize: total}
+	counter := &progressCounter{t: t, entry: sftpEntry{rel: name, size: total}}
+	copyErr := renameTar(dstW, srcR, srcName, name, func(r io.Reader) io.Reader {
+		counter.reader = r
+		return counter
+	})
+	dstW.CloseWithError(copyErr)
+	if copyErr == nil {
+		// tar는 아카이브 끝 뒤에도 레코드 크기만큼 채워 보내므로 나머지를 비움
+		io.Copy(io.Discard, srcR)
+	} else {
+		cancel()
+	}
+	srcR.CloseWithError(copyErr)
+
+	// 상대 쪽 실패로 취소된 스트림의 오류는 원인이 아님
+	readErr, writeErr := <-readDone, <-writeDone
+	switch {
+	case ctx.Err() != nil:
+		return counter.done, ctx.Err()
+	case readErr != nil && !errors.Is(readErr, context.Canceled):
+		return counter.done, fmt.Errorf("reading from %s failed: %w", src.Server.ServerName, readErr)
+	case writeErr != nil && !errors.Is(writeErr, context.Canceled):
+		return counter.done, fmt.Errorf("writing to %s failed: %w", dst.Server.ServerName, writeErr)
+	}
+	return counter.done, copyErr
+}
+
+// renameTar copies a tar stream, renaming its top-level entry from oldName to newName.
+// count wraps each file's content so progress follows the data rather than the headers.
+func renameTar(w io.Writer, r io.Reader, oldName, newName string, count func(io.Reader) io.Reader) error {
+	tr := tar.NewReader(r)
+	tw := tar.NewWriter(w)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if rest, ok := strings.CutPrefix(header.Name, oldName); ok && (rest == "" || rest[0] == '/') {
+			header.Name = newName + rest
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, count(tr)); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// remoteSize is the disk usage of p on an endpoint in bytes, or 0 when it cannot be measured.
+func remoteSize(e Endpoint, p string) int64 {
+	command := fmt.Sprintf("du -sk %s", remoteShellPath(p))
+	if e.Container != "" {
+		t := targetFor(e.Server, e.Container)
+		command = t.command(t.exec(false, false) + " du -sk " + shellQuote(p))
+	}
+
+	output, err := ExecuteRemoteCommand(e.Server, command)
+	fields := strings.Fields(output)
+	if err != nil || len(fields) == 0 {
+		return 0
+	}
+	kb, _ := strconv.ParseInt(fields[0], 10, 64)
+	return kb * 1024
+}
+
+// endpointDestination resolves where a source named srcName lands on dst.
+func endpointDestination(dst Endpoint, srcName string) (dir, name string, err error) {
+	if dst.Container != "" {
+		return containerDestination(dst.Server, dst.Container, dst.Path, srcName)
+	}
+	if dst.Path == "" || strings.HasSuffix(dst.Path, "/") {
+		return dst.Path, srcName, nil
+	}
+
+	output, err := ExecuteRemoteCommand(dst.Server, fmt.Sprintf("if [ -d %s ]; then echo dir; fi", remoteShellPath(dst.Path)))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to inspect %s on %s: %w", dst.Path, dst.Server.ServerName, err)
+	}
+	if output == "dir" {
+		return dst.Path, srcName, nil
+	}
+	return path.Dir(dst.Path), path.Base(dst.Path), nil
+}
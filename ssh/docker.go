@@ -1,42 +1,199 @@
 package ssh
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"remotelink/models"
+	"slices"
 	"strings"
 )
 
-// FetchContainers connects to a remote server via SSH, checks if docker is installed,
-// runs docker ps, and returns the list of running containers.
-// Uses a single SSH call for both docker check and container listing.
-func FetchContainers(server models.Server) ([]models.Container, error) {
-	output, err := ExecuteRemoteCommand(server, "which docker > /dev/null 2>&1 && docker ps --format '{{.Names}}\t{{.Image}}'")
-	if err != nil {
-		return nil, fmt.Errorf("Docker is not installed on the remote server: %w", err)
-	}
+// containersFormat prints one JSON object per container. Like statsFormat it names the fields
+// itself so docker, podman and nerdctl print the same keys.
+var containersFormat = `{"id":{{json .ID}},"name":{{json .Names}},"image":{{json .Image}},"status":{{json .Status}},` +
+	`"ports":{{json .Ports}},"project":{{json (.Label "` + composeProjectLabel + `")}},"service":{{json (.Label "` + composeServiceLabel + `")}}}`
+
+// restartsFormat is the inspect format that pairs a container's name with its restart count.
+const restartsFormat = `{"inspect":{{json .Name}},"restarts":{{.RestartCount}}}`
 
-	if output == "" {
-		return nil, nil
+// FetchContainers connects to a remote server via SSH, finds its container runtime,
+// runs ps, and returns the running containers, or every container when all is set, with their
+// compose project and service, status, health, published ports and restart count.
+// Uses a single SSH call for runtime detection, listing and the restart counts.
+func FetchContainers(server models.Server, all bool) ([]models.Container, error) {
+	ps := `"$rt" ps`
+	if all {
+		ps += " -a"
+	}
+	// 목록과 inspect 사이에 컨테이너가 사라져도 목록은 그대로 사용
+	output, err := ExecuteRemoteCommand(server, runtimeCommand(server,
+		fmt.Sprintf("%s --format %s", ps, shellQuote(containersFormat)),
+		fmt.Sprintf("ids=$(%s -q)", ps),
+		fmt.Sprintf(`{ [ -z "$ids" ] || "$rt" inspect --format %s $ids 2>/dev/null || true; }`, shellQuote(restartsFormat))))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not installed on the remote server: %w", runtimeName(server), err)
 	}
 
 	var containers []models.Container
-	lines := strings.Split(output, "\n")
-	for _, line := range lines {
+	restarts := map[string]int{}
+	for _, line := range strings.Split(output, "\n") {
+		var raw struct {
+			ID       string `json:"id"`
+			Name     string `json:"name"`
+			Image    string `json:"image"`
+			Status   string `json:"status"`
+			Ports    string `json:"ports"`
+			Project  string `json:"project"`
+			Service  string `json:"service"`
+			Inspect  string `json:"inspect"`
+			Restarts int    `json:"restarts"`
+		}
 		line = strings.TrimSpace(line)
-		if line == "" {
+		if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &raw) != nil {
 			continue
 		}
-
-		parts := strings.SplitN(line, "\t", 2)
-		if len(parts) != 2 {
+		if raw.Inspect != "" {
+			restarts[strings.TrimPrefix(raw.Inspect, "/")] = raw.Restarts
+			continue
+		}
+		if raw.Name == "" {
 			continue
 		}
 
 		containers = append(containers, models.Container{
-			ContainerName: strings.TrimSpace(parts[0]),
-			ImageName:     strings.TrimSpace(parts[1]),
+			ContainerName: raw.Name,
+			ImageName:     raw.Image,
+			Project:       raw.Project,
+			Service:       raw.Service,
+			ID:            raw.ID,
+			State:         containerState(raw.Status),
+			Status:        raw.Status,
+			Uptime:        containerUptime(raw.Status),
+			Health:        containerHealth(raw.Status),
+			Ports:         publishedPorts(raw.Ports),
 		})
 	}
+	for i := range containers {
+		containers[i].RestartCount = restarts[containers[i].ContainerName]
+	}
 
 	return containers, nil
 }
+
+// containerHealth extracts the health check result from a status such as "Up 2 hours (healthy)".
+func containerHealth(status string) string {
+	switch {
+	case strings.Contains(status, "(healthy)"):
+		return "healthy"
+	case strings.Contains(status, "(unhealthy)"):
+		return "unhealthy"
+	case strings.Contains(status, "(health: starting)"):
+		return "starting"
+	}
+	return ""
+}
+
+// containerUptime extracts "2 hours" from a running container's status "Up 2 hours (healthy)".
+func containerUptime(status string) string {
+	uptime, ok := strings.CutPrefix(status, "Up ")
+	if !ok {
+		return ""
+	}
+	if i := strings.Index(uptime, " ("); i >= 0 {
+		uptime = uptime[:i]
+	}
+	return uptime
+}
+
+// publishedPorts picks the published mappings out of the ps ports column, dropping the bind
+// address so the IPv4 and IPv6 bindings of the same port are listed once.
+func publishedPorts(ports string) []string {
+	var published []string
+	for _, port := range strings.Split(ports, ",") {
+		host, container, ok := strings.Cut(strings.TrimSpace(port), "->")
+		if !ok {
+			continue
+		}
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[i+1:]
+		}
+		mapping := host + "->" + container
+		if !slices.Contains(published, mapping) {
+			published = append(published, mapping)
+		}
+	}
+	return published
+}
+
+// ContainerIP returns the IP address of a running container on the server, from the first
+// network it is attached to, or of a pod.
+func ContainerIP(server models.Server, container string) (string, error) {
+	if pod, ok := parsePodRef(container); ok {
+		return podIP(server, pod)
+	}
+
+	output, err := ExecuteRemoteCommand(server, runtimeCommand(server, fmt.Sprintf(
+		`"$rt" inspect -f '{{range .NetworkSettings.Networks}}{{.IPAddress}} {{end}}' %s`, shellQuote(container))))
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect container '%s': %w", container, err)
+	}
+
+	ips := strings.Fields(output)
+	if len(ips) == 0 {
+		return "", fmt.Errorf("container '%s' has no IP address", container)
+	}
+	return ips[0], nil
+}
+
+// Container actions accepted by ContainerAction.
+const (
+	ContainerStart   = "start"
+	ContainerStop    = "stop"
+	ContainerRestart = "restart"
+	ContainerRemove  = "rm"
+)
+
+// Container states derived from the ps status column.
+const (
+	StateRunning    = "running"
+	StatePaused     = "paused"
+	StateRestarting = "restarting"
+	StateExited     = "exited"
+	StateCreated    = "created"
+	StateDead       = "dead"
+)
+
+// containerState maps a status such as "Up 2 hours (Paused)" onto a state, which older
+// docker versions cannot print directly.
+func containerState(status string) string {
+	switch {
+	case strings.HasPrefix(status, "Up"):
+		if strings.Contains(status, "(Paused)") {
+			return StatePaused
+		}
+		return StateRunning
+	case strings.HasPrefix(status, "Restarting"):
+		return StateRestarting
+	case strings.HasPrefix(status, "Exited"):
+		return StateExited
+	case strings.HasPrefix(status, "Created"):
+		return StateCreated
+	}
+	return StateDead
+}
+
+// ContainerAction starts, stops, restarts or removes a container. force lets rm remove a running container.
+// It runs without the usual command timeout, as stopping waits out the container's grace period.
+func ContainerAction(server models.Server, action, container string, force bool) error {
+	command := `"$rt" ` + action
+	if action == ContainerRemove && force {
+		command += " -f"
+	}
+	var output bytes.Buffer
+	err := BackendFor(server).Stream(server, runtimeCommand(server, command+" "+shellQuote(container)), nil, &output, &output)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w\n%s", action, container, err, strings.TrimSpace(output.String()))
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// Connection errors returned by the native backend. Callers can match them with errors.Is.
+var (
+	ErrAuthFailed      = errors.New("authentication failed")
+	ErrHostUnreachable = errors.New("host unreachable")
+	ErrHostKeyMismatch = errors.New("host key mismatch")
+)
+
+// HostKeyMismatchError reports that a server presented a host key that differs from the one on record.
+type HostKeyMismatchError struct {
+	Host     string
+	Expected []string
+	Got      string
+}
+
+func (e *HostKeyMismatchError) Error() string {
+	return fmt.Sprintf("host key mismatch for %s: got %s, expected %s",
+		e.Host, e.Got, strings.Join(e.Expected, " or "))
+}
+
+// Is makes errors.Is(err, ErrHostKeyMismatch) match a *HostKeyMismatchError.
+func (e *HostKeyMismatchError) Is(target error) bool {
+	return target == ErrHostKeyMismatch
+}
+
+// classifyHandshakeError maps errors from the SSH handshake onto the typed errors above.
+func classifyHandshakeError(addr string, err error) error {
+	var mismatch *HostKeyMismatchError
+	if errors.As(err, &mismatch) {
+		return mismatch
+	}
+	if errors.Is(err, ErrHostKeyUnknown) {
+		if inner := errors.Unwrap(err); inner != nil {
+			return inner
+		}
+		return err
+	}
+	if strings.Contains(err.Error(), "unable to authenticate") {
+		return fmt.Errorf("%w: %s: %w", ErrAuthFailed, addr, err)
+	}
+	return fmt.Errorf("%w: %s: %w", ErrHostUnreachable, addr, err)
+}
+
+// sshError is a failure of the ssh binary itself, as opposed to the remote command it ran.
+type sshError struct {
+	err error
+	msg string
+}
+
+func (e *sshError) Error() string {
+	return fmt.Sprintf("%v\n%s", e.err, e.msg)
+}
+
+func (e *sshError) Unwrap() error {
+	return e.err
+}
+
+// ExitStatus returns the exit status of a remote command that ran but exited non-zero.
+// ok is false when err is not caused by the remote command itself (connection or auth failures).
+func ExitStatus(err error) (status int, ok bool) {
+	var gosshErr *gossh.ExitError
+	if errors.As(err, &gosshErr) {
+		return gosshErr.ExitStatus(), true
+	}
+	var sshErr *sshError
+	if errors.As(err, &sshErr) {
+		return 0, false
+	}
+	var execErr *exec.ExitError
+	if errors.As(err, &execErr) && execErr.ExitCode() >= 0 {
+		return execErr.ExitCode(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,46 @@
+package ssh
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// fakeSSH stands in for ssh: it exits 255 either way, logging to the -E file only when told to fail itself.
+const fakeSSH = `#!/bin/sh
+log=$2
+shift 4
+if [ "$1" = fail ]; then
+	echo "ssh: connect to host example port 22: Connection refused" > "$log"
+fi
+exit 255
+`
+
+func TestRunSSHExitStatus(t *testing.T) {
+	bin := filepath.Join(t.TempDir(), "ssh")
+	if err := os.WriteFile(bin, []byte(fakeSSH), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		arg        string
+		wantStatus int
+		wantOK     bool
+	}{
+		{arg: "remote", wantStatus: 255, wantOK: true},
+		{arg: "fail", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			err := runSSH(exec.Command(bin, tt.arg))
+			if err == nil {
+				t.Fatal("runSSH() succeeded, want exit 255")
+			}
+			status, ok := ExitStatus(err)
+			if status != tt.wantStatus || ok != tt.wantOK {
+				t.Errorf("ExitStatus() = %d, %v, want %d, %v (err: %v)", status, ok, tt.wantStatus, tt.wantOK, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"remotelink/models"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// execBackend shells out to the locally installed ssh and scp binaries.
+type execBackend struct{}
+
+// nullDevice returns the null device path for the current OS.
+func nullDevice() string {
+	if runtime.GOOS == "windows" {
+		return "NUL"
+	}
+	return "/dev/null"
+}
+
+// buildSSHArgs builds common ssh arguments for the given server.
+func buildSSHArgs(server models.Server) ([]string, error) {
+	args := []string{
+		"-p", fmt.Sprintf("%d", server.Port),
+	}
+
+	if server.KeyPath != "" {
+		args = append(args, "-i", server.KeyPath)
+	}
+
+	jumpArgs, err := jumpOptions(server)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(args, jumpArgs...), nil
+}
+
+// jumpOptions builds a ProxyCommand that tunnels through the server's jump chain with ssh -W.
+// Each hop keeps its own port, key and host key policy, which ProxyJump (-J) cannot express.
+func jumpOptions(server models.Server) ([]string, error) {
+	hops, err := jumpHops(server)
+	if err != nil || len(hops) == 0 {
+		return nil, err
+	}
+
+	var proxy string
+	for _, hop := range hops {
+		hopArgs := []string{"ssh", "-p", fmt.Sprintf("%d", hop.Port), "-o", "BatchMode=yes"}
+		hopArgs = append(hopArgs, hostKeyOptions(hop, false)...)
+		if hop.KeyPath != "" {
+			hopArgs = append(hopArgs, "-i", hop.KeyPath)
+		}
+		if proxy != "" {
+			// 바깥 ssh가 %h:%p를 먼저 치환하지 않도록 한 단계 이스케이프
+			hopArgs = append(hopArgs, "-o", "ProxyCommand="+strings.ReplaceAll(proxy, "%", "%%"))
+		}
+		hopArgs = append(hopArgs, "-W", "%h:%p", fmt.Sprintf("%s@%s", hop.Username, hop.HostIp))
+
+		quoted := make([]string, len(hopArgs))
+		for i, arg := range hopArgs {
+			quoted[i] = shellQuote(arg)
+		}
+		proxy = strings.Join(quoted, " ")
+	}
+
+	return []string{"-o", "ProxyCommand=" + proxy}, nil
+}
+
+// hostKeyOptions maps the server's host key policy onto OpenSSH options using remotelink's known_hosts.
+// Unknown hosts can only be confirmed by ssh itself in interactive sessions.
+func hostKeyOptions(server models.Server, interactive bool) []string {
+	checking := "yes"
+	knownHosts := KnownHostsPath()
+
+	switch hostKeyPolicy(server) {
+	case HostKeyOff:
+		checking = "no"
+		knownHosts = nullDevice()
+	case HostKeyTOFU:
+		if interactive {
+			checking = "ask"
+		}
+	}
+
+	return []string{
+		"-o", "StrictHostKeyChecking=" + checking,
+		"-o", "UserKnownHostsFile=" + knownHosts,
+		"-o", "GlobalKnownHostsFile=" + nullDevice(),
+	}
+}
+
+// buildSCPArgs builds common SCP arguments for the given server.
+func buildSCPArgs(server models.Server) ([]string, error) {
+	args := []string{
+		"-F", nullDevice(),
+		"-P", fmt.Sprintf("%d", server.Port),
+		"-o", "BatchMode=yes",
+		"-r",
+	}
+	args = append(args, hostKeyOptions(server, false)...)
+
+	if server.KeyPath != "" {
+		args = append(args, "-i", server.KeyPath)
+	}
+
+	jumpArgs, err := jumpOptions(server)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(args, jumpArgs...), nil
+}
+
+func (execBackend) Run(server models.Server, command string) (string, error) {
+	sshArgs, err := buildSSHArgs(server)
+	if err != nil {
+		return "", err
+	}
+	sshArgs = append(sshArgs, hostKeyOptions(server, false)...)
+	sshArgs = append(sshArgs,
+		"-o", "ConnectTimeout=5",
+		"-o", "BatchMode=yes",
+		fmt.Sprintf("%s@%s", server.Username, server.HostIp),
+		command,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "ssh", sshArgs...)
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("SSH command timed out (10s)")
+	}
+	if err != nil {
+		return "", fmt.Errorf("SSH command failed: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (execBackend) Interactive(server models.Server, command string) error {
+	sshArgs, err := buildSSHArgs(server)
+	if err != nil {
+		return err
+	}
+	sshArgs = append(sshArgs, hostKeyOptions(server, true)...)
+
+	// 명령이 있을 때는 터미널인 경우에만 TTY 할당
+	if command != "" && term.IsTerminal(int(os.Stdin.Fd())) {
+		sshArgs = append(sshArgs, "-t")
+	}
+
+	sshArgs = append(sshArgs, fmt.Sprintf("%s@%s", server.Username, server.HostIp))
+	if command != "" {
+		sshArgs = append(sshArgs, command)
+	}
+
+	sshCmd := exec.Command("ssh", sshArgs...)
+	sshCmd.Stdin = os.Stdin
+	sshCmd.Stdout = os.Stdout
+	sshCmd.Stderr = os.Stderr
+
+	return runSSH(sshCmd)
+}
+
+func (execBackend) Upload(server models.Server, localPath, remotePath string) error {
+	args, err := buildSCPArgs(server)
+	if err != nil {
+		return err
+	}
+	args = append(args, localPath, fmt.Sprintf("%s@%s:%s", server.Username, server.HostIp, remotePath))
+
+	cmd := exec.Command("scp", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("scp upload failed: %w", err)
+	}
+	return nil
+}
+
+func (execBackend) Download(server models.Server, remotePath, localPath string) error {
+	args, err := buildSCPArgs(server)
+	if err != nil {
+		return err
+	}
+	args = append(args, fmt.Sprintf("%s@%s:%s", server.Username, server.HostIp, remotePath), localPath)
+
+	cmd := exec.Command("scp", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("scp download failed: %w", err)
+	}
+	return nil
+}
+
+func (execBackend) Stream(server models.Server, command string, stdin io.Reader, stdout, stderr io.Writer) error {
+	sshArgs, err := buildSSHArgs(server)
+	if err != nil {
+		return err
+	}
+	sshArgs = append(sshArgs, hostKeyOptions(server, false)...)
+	sshArgs = append(sshArgs,
+		"-o", "ConnectTimeout=5",
+		"-o", "BatchMode=yes",
+		fmt.Sprintf("%s@%s", server.Username, server.HostIp),
+		command,
+	)
+
+	var captured bytes.Buffer
+	cmd := exec.Command("ssh", sshArgs...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if stderr == nil {
+		cmd.Stderr = &captured
+	}
+
+	if err := runSSH(cmd); err != nil {
+		return fmt.Errorf("%w\n%s", err, strings.TrimSpace(captured.String()))
+	}
+	return nil
+}
+
+// runSSH runs an ssh command with ssh's own diagnostics written to a temporary file through -E.
+// ssh exits 255 both when it fails and when the remote command does; a failure of ssh itself
+// leaves a message in the file and is returned as an *sshError.
+func runSSH(cmd *exec.Cmd) error {
+	logFile, err := os.CreateTemp("", "remotelink-ssh-*.log")
+	if err != nil {
+		return err
+	}
+	logFile.Close()
+	defer os.Remove(logFile.Name())
+
+	// 옵션은 호스트 인자보다 앞에 있어야 함; INFO 수준의 "Connection closed" 같은 메시지는 제외
+	cmd.Args = slices.Insert(cmd.Args, 1, "-E", logFile.Name(), "-o", "LogLevel=ERROR")
+	err = cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 255 {
+		if msg, _ := os.ReadFile(logFile.Name()); len(bytes.TrimSpace(msg)) > 0 {
+			return &sshError{err: err, msg: strings.TrimSpace(string(msg))}
+		}
+	}
+	return err
+}
+
+func (execBackend) Forward(ctx context.Context, server models.Server, forwards []models.Forward, opts ForwardOptions) error {
+	sshArgs, err := buildSSHArgs(server)
+	if err != nil {
+		return err
+	}
+	sshArgs = append(sshArgs, hostKeyOptions(server, false)...)
+	sshArgs = append(sshArgs,
+		"-N",
+		"-o", "ConnectTimeout=5",
+		"-o", "BatchMode=yes",
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "ServerAliveInterval=15",
+	)
+
+	var probes []string
+	for _, forward := range forwards {
+		switch forward.Type {
+		case ForwardLocal:
+			listen, _ := listenAddress(forward.Local)
+			sshArgs = append(sshArgs, "-L", listen+":"+forward.Remote)
+			probes = append(probes, probeAddress(listen))
+		case ForwardRemote:
+			listen, _ := listenAddress(forward.Remote)
+			sshArgs = append(sshArgs, "-R", listen+":"+forward.Local)
+		case ForwardDynamic:
+			listen, _ := listenAddress(forward.Local)
+			sshArgs = append(sshArgs, "-D", listen)
+			probes = append(probes, probeAddress(listen))
+		}
+	}
+	sshArgs = append(sshArgs, fmt.Sprintf("%s@%s", server.Username, server.HostIp))
+
+	cmd := exec.CommandContext(ctx, "ssh", sshArgs...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("ssh forwarding failed: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	// ssh는 포워딩 준비 시점을 알려주지 않으므로 로컬 리슨 포트가 열릴 때까지 확인
+	// -R만 있으면 확인할 포트가 없으므로, ExitOnForwardFailure로 실패 시 종료되지 않고 일정 시간 버티면 준비된 것으로 간주
+	start := time.Now()
+	tick := time.NewTicker(forwardProbeInterval)
+	defer tick.Stop()
+	for ready := false; !ready; {
+		select {
+		case err := <-exited:
+			return forwardExitError(ctx, err)
+		case <-tick.C:
+			if len(probes) == 0 {
+				ready = time.Since(start) >= remoteForwardGrace
+			} else {
+				ready = listening(probes)
+			}
+		}
+	}
+	if opts.Ready != nil {
+		opts.Ready()
+	}
+	return forwardExitError(ctx, <-exited)
+}
+
+const (
+	// forwardProbeInterval is how often the exec backend checks whether ssh's listeners are up.
+	forwardProbeInterval = 100 * time.Millisecond
+	// remoteForwardGrace is how long ssh must stay up before remote-only forwards count as ready.
+	remoteForwardGrace = 3 * time.Second
+)
+
+func forwardExitError(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("ssh forwarding failed: %w", err)
+	}
+	return nil
+}
+
+// probeAddress turns a wildcard listen address into one that can be dialed.
+func probeAddress(listen string) string {
+	host, port, _ := net.SplitHostPort(listen)
+	switch host {
+	case "0.0.0.0":
+		host = "127.0.0.1"
+	case "::":
+		host = "::1"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// listening reports whether every address accepts a connection.
+func listening(addrs []string) bool {
+	for _, addr := range addrs {
+		conn, err := net.DialTimeout("tcp", addr, forwardProbeInterval)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+	}
+	return true
+}
@@ -0,0 +1,348 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"remotelink/models"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// forwardKeepalive is how often an idle forwarding connection is probed, and how long a probe may take.
+const forwardKeepalive = 15 * time.Second
+
+// Forward types accepted by the type field of a forwarding preset.
+const (
+	ForwardLocal   = "local"
+	ForwardRemote  = "remote"
+	ForwardDynamic = "dynamic"
+)
+
+// ParseForward parses an OpenSSH-style forwarding spec as given to -L, -R or -D:
+//
+//	local:   [bind:]port:host:hostport
+//	remote:  [bind:]port:host:hostport
+//	dynamic: [bind:]port
+//
+// With container set, a local spec is [bind:]port:containerport instead.
+func ParseForward(forwardType, spec, container string) (models.Forward, error) {
+	parts := splitForwardSpec(spec)
+	forward := models.Forward{Type: forwardType, Container: container}
+
+	switch {
+	case forwardType == ForwardLocal && container != "" && (len(parts) == 2 || len(parts) == 3):
+		forward.Local = joinSpec(parts[:len(parts)-1])
+		forward.Remote = parts[len(parts)-1]
+	case forwardType == ForwardLocal && (len(parts) == 3 || len(parts) == 4):
+		forward.Local = joinSpec(parts[:len(parts)-2])
+		forward.Remote = joinSpec(parts[len(parts)-2:])
+	case forwardType == ForwardRemote && (len(parts) == 3 || len(parts) == 4):
+		forward.Remote = joinSpec(parts[:len(parts)-2])
+		forward.Local = joinSpec(parts[len(parts)-2:])
+	case forwardType == ForwardDynamic && (len(parts) == 1 || len(parts) == 2):
+		forward.Local = joinSpec(parts)
+	default:
+		return models.Forward{}, fmt.Errorf("invalid %s forward %q", forwardType, spec)
+	}
+
+	if err := ValidateForward(forward); err != nil {
+		return models.Forward{}, err
+	}
+	return forward, nil
+}
+
+// ValidateForward checks that the forward's addresses fit its type.
+func ValidateForward(forward models.Forward) error {
+	var listen, target string
+	switch forward.Type {
+	case ForwardLocal:
+		listen, target = forward.Local, forward.Remote
+	case ForwardRemote:
+		listen, target = forward.Remote, forward.Local
+	case ForwardDynamic:
+		listen = forward.Local
+	default:
+		return fmt.Errorf("unknown forward type %q (expected %q, %q or %q)", forward.Type, ForwardLocal, ForwardRemote, ForwardDynamic)
+	}
+
+	if forward.Container != "" && forward.Type != ForwardLocal {
+		return fmt.Errorf("container forwards must be of type %q", ForwardLocal)
+	}
+	if _, err := listenAddress(listen); err != nil {
+		return err
+	}
+	if forward.Container != "" {
+		if _, err := parsePort(target); err != nil {
+			return fmt.Errorf("invalid container port %q", target)
+		}
+		return nil
+	}
+	if forward.Type != ForwardDynamic {
+		host, port, err := net.SplitHostPort(target)
+		if err != nil || host == "" {
+			return fmt.Errorf("invalid forward target %q (expected host:port)", target)
+		}
+		if _, err := parsePort(port); err != nil {
+			return fmt.Errorf("invalid port in %q", target)
+		}
+	}
+	return nil
+}
+
+// ResolveForwards replaces container targets with the container's current IP address.
+func ResolveForwards(server models.Server, forwards []models.Forward) ([]models.Forward, error) {
+	resolved := make([]models.Forward, len(forwards))
+	for i, forward := range forwards {
+		if forward.Container != "" {
+			ip, err := ContainerIP(server, forward.Container)
+			if err != nil {
+				return nil, err
+			}
+			forward.Remote = net.JoinHostPort(ip, forward.Remote)
+			forward.Container = ""
+		}
+		resolved[i] = forward
+	}
+	return resolved, nil
+}
+
+// DescribeForward formats a resolved forward for display, listening side first.
+func DescribeForward(forward models.Forward) string {
+	switch forward.Type {
+	case ForwardLocal:
+		listen, _ := listenAddress(forward.Local)
+		return fmt.Sprintf("%s → %s (remote)", listen, forward.Remote)
+	case ForwardRemote:
+		listen, _ := listenAddress(forward.Remote)
+		return fmt.Sprintf("%s (remote) → %s", listen, forward.Local)
+	default:
+		listen, _ := listenAddress(forward.Local)
+		return fmt.Sprintf("%s → SOCKS5 proxy", listen)
+	}
+}
+
+// ForwardStats counts traffic through a server's forwards. Sent is what went to the server
+// side and Received what came back. The native backend is the only one that counts.
+type ForwardStats struct {
+	Sent        atomic.Int64
+	Received    atomic.Int64
+	Connections atomic.Int64
+}
+
+// ForwardOptions are optional hooks for a forwarding session.
+type ForwardOptions struct {
+	// Stats, when set, is updated as connections are forwarded.
+	Stats *ForwardStats
+	// Ready, when set, is called once all forwards are listening.
+	Ready func()
+}
+
+// Forward runs the forwards on the server until ctx is cancelled or the connection drops.
+func Forward(ctx context.Context, server models.Server, forwards []models.Forward, opts ForwardOptions) error {
+	return BackendFor(server).Forward(ctx, server, forwards, opts)
+}
+
+// listenAddress expands a [bind:]port listen spec. A bare port binds to loopback and "*" to all interfaces.
+func listenAddress(spec string) (string, error) {
+	host, port := "127.0.0.1", spec
+	if i := strings.LastIndex(spec, ":"); i >= 0 {
+		host, port = strings.Trim(spec[:i], "[]"), spec[i+1:]
+		if host == "*" || host == "" {
+			host = "0.0.0.0"
+		}
+	}
+	if _, err := parsePort(port); err != nil {
+		return "", fmt.Errorf("invalid listen address %q", spec)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+func parsePort(s string) (int, error) {
+	port, err := net.LookupPort("tcp", s)
+	if err != nil || port == 0 {
+		return 0, fmt.Errorf("invalid port %q", s)
+	}
+	return port, nil
+}
+
+// splitForwardSpec splits on ':' outside of [bracketed] IPv6 addresses.
+func splitForwardSpec(spec string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range spec {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ':':
+			if depth == 0 {
+				parts = append(parts, spec[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, spec[start:])
+}
+
+// joinSpec joins host and port parts back together, bracketing IPv6 hosts.
+func joinSpec(parts []string) string {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return net.JoinHostPort(strings.Trim(parts[0], "[]"), parts[1])
+}
+
+// nativeForwarder serves forwards over one SSH connection.
+type nativeForwarder struct {
+	client    *Client
+	stats     *ForwardStats
+	listeners []net.Listener
+	wg        sync.WaitGroup
+}
+
+func (nativeBackend) Forward(ctx context.Context, server models.Server, forwards []models.Forward, opts ForwardOptions) error {
+	client, err := Dial(server)
+	if err != nil {
+		return err
+	}
+	stats := opts.Stats
+	if stats == nil {
+		stats = &ForwardStats{}
+	}
+	f := &nativeForwarder{client: client, stats: stats}
+	defer f.close()
+
+	for _, forward := range forwards {
+		if err := f.listen(forward); err != nil {
+			return err
+		}
+	}
+	if opts.Ready != nil {
+		opts.Ready()
+	}
+
+	lost := make(chan error, 1)
+	go func() { lost <- client.Wait() }()
+
+	// 응답 없는 연결을 감지하기 위한 keepalive
+	keepalive := time.NewTicker(forwardKeepalive)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-lost:
+			return fmt.Errorf("connection to %s lost: %v", server.ServerName, err)
+		case <-keepalive.C:
+			timer := time.AfterFunc(forwardKeepalive, func() { client.Close() })
+			_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+			timer.Stop()
+			if err != nil {
+				return fmt.Errorf("connection to %s lost: keepalive failed: %v", server.ServerName, err)
+			}
+		}
+	}
+}
+
+// listen opens the listening side of forward and starts accepting connections.
+func (f *nativeForwarder) listen(forward models.Forward) error {
+	var listener net.Listener
+	var dial func(net.Conn) (net.Conn, error)
+	var err error
+
+	switch forward.Type {
+	case ForwardLocal:
+		addr, _ := listenAddress(forward.Local)
+		listener, err = net.Listen("tcp", addr)
+		dial = func(net.Conn) (net.Conn, error) { return f.client.Dial("tcp", forward.Remote) }
+	case ForwardRemote:
+		addr, _ := listenAddress(forward.Remote)
+		listener, err = f.client.Listen("tcp", addr)
+		dial = func(net.Conn) (net.Conn, error) { return net.Dial("tcp", forward.Local) }
+	case ForwardDynamic:
+		addr, _ := listenAddress(forward.Local)
+		listener, err = net.Listen("tcp", addr)
+		dial = func(conn net.Conn) (net.Conn, error) { return socksHandshake(conn, f.client.Dial) }
+	}
+	if err != nil {
+		return fmt.Errorf("failed to listen for %s: %w", DescribeForward(forward), err)
+	}
+	f.listeners = append(f.listeners, listener)
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				target, err := dial(conn)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "forward %s: %v\n", DescribeForward(forward), err)
+					conn.Close()
+					return
+				}
+				f.stats.Connections.Add(1)
+
+				// 원격 포워딩은 받은 연결이 서버 쪽
+				if forward.Type == ForwardRemote {
+					pipe(target, conn, f.stats)
+				} else {
+					pipe(conn, target, f.stats)
+				}
+			}()
+		}
+	}()
+	return nil
+}
+
+func (f *nativeForwarder) close() {
+	for _, listener := range f.listeners {
+		listener.Close()
+	}
+	f.client.Close()
+	f.wg.Wait()
+}
+
+// pipe copies between the local and server-side connections until both directions are done,
+// then closes them. Bytes are counted as they flow.
+func pipe(local, remote net.Conn, stats *ForwardStats) {
+	var wg sync.WaitGroup
+	copyHalf := func(dst, src net.Conn, counter *atomic.Int64) {
+		defer wg.Done()
+		io.Copy(countingWriter{dst, counter}, src)
+		if cw, ok := dst.(interface{ CloseWrite() error }); ok {
+			cw.CloseWrite()
+		} else {
+			dst.Close()
+		}
+	}
+
+	wg.Add(2)
+	go copyHalf(remote, local, &stats.Sent)
+	go copyHalf(local, remote, &stats.Received)
+	wg.Wait()
+	local.Close()
+	remote.Close()
+}
+
+// countingWriter adds the bytes written through it to a counter.
+type countingWriter struct {
+	w       io.Writer
+	counter *atomic.Int64
+}
+
+func (c countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.counter.Add(int64(n))
+	return n, err
+}
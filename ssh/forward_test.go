@@ -0,0 +1,120 @@
+package ssh
+
+import (
+	"reflect"
+	"remotelink/models"
+	"testing"
+)
+
+func TestParseForward(t *testing.T) {
+	tests := []struct {
+		name        string
+		forwardType string
+		spec        string
+		container   string
+		want        models.Forward
+		wantErr     bool
+	}{
+		{
+			name:        "local",
+			forwardType: ForwardLocal,
+			spec:        "8080:localhost:80",
+			want:        models.Forward{Type: ForwardLocal, Local: "8080", Remote: "localhost:80"},
+		},
+		{
+			name:        "local with bind address",
+			forwardType: ForwardLocal,
+			spec:        "0.0.0.0:8080:db:5432",
+			want:        models.Forward{Type: ForwardLocal, Local: "0.0.0.0:8080", Remote: "db:5432"},
+		},
+		{
+			name:        "local with ipv6 addresses",
+			forwardType: ForwardLocal,
+			spec:        "[::1]:8080:[fd00::2]:5432",
+			want:        models.Forward{Type: ForwardLocal, Local: "[::1]:8080", Remote: "[fd00::2]:5432"},
+		},
+		{
+			name:        "remote",
+			forwardType: ForwardRemote,
+			spec:        "9000:localhost:3000",
+			want:        models.Forward{Type: ForwardRemote, Remote: "9000", Local: "localhost:3000"},
+		},
+		{
+			name:        "remote with bind address",
+			forwardType: ForwardRemote,
+			spec:        "*:9000:localhost:3000",
+			want:        models.Forward{Type: ForwardRemote, Remote: "*:9000", Local: "localhost:3000"},
+		},
+		{
+			name:        "dynamic",
+			forwardType: ForwardDynamic,
+			spec:        "1080",
+			want:        models.Forward{Type: ForwardDynamic, Local: "1080"},
+		},
+		{
+			name:        "dynamic with bind address",
+			forwardType: ForwardDynamic,
+			spec:        "127.0.0.1:1080",
+			want:        models.Forward{Type: ForwardDynamic, Local: "127.0.0.1:1080"},
+		},
+		{
+			name:        "container",
+			forwardType: ForwardLocal,
+			spec:        "8080:80",
+			container:   "web",
+			want:        models.Forward{Type: ForwardLocal, Local: "8080", Remote: "80", Container: "web"},
+		},
+		{
+			name:        "container with bind address",
+			forwardType: ForwardLocal,
+			spec:        "0.0.0.0:8080:80",
+			container:   "web",
+			want:        models.Forward{Type: ForwardLocal, Local: "0.0.0.0:8080", Remote: "80", Container: "web"},
+		},
+		{name: "local missing target", forwardType: ForwardLocal, spec: "8080", wantErr: true},
+		{name: "local bad port", forwardType: ForwardLocal, spec: "http-ish:localhost:80", wantErr: true},
+		{name: "local bad target port", forwardType: ForwardLocal, spec: "8080:localhost:0", wantErr: true},
+		{name: "dynamic too many parts", forwardType: ForwardDynamic, spec: "a:1080:b", wantErr: true},
+		{name: "remote container", forwardType: ForwardRemote, spec: "9000:80", container: "web", wantErr: true},
+		{name: "unknown type", forwardType: "sideways", spec: "8080", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseForward(tt.forwardType, tt.spec, tt.container)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseForward() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseForward() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseForward() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitForwardSpec(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []string
+	}{
+		{"8080", []string{"8080"}},
+		{"8080:localhost:80", []string{"8080", "localhost", "80"}},
+		{"[::1]:8080", []string{"[::1]", "8080"}},
+		{"[::1]:8080:[fd00::2]:5432", []string{"[::1]", "8080", "[fd00::2]", "5432"}},
+		{":8080", []string{"", "8080"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			if got := splitForwardSpec(tt.spec); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitForwardSpec(%q) = %q, want %q", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"remotelink/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Health is the result of checking one server. Later checks are skipped when an earlier one fails.
+type Health struct {
+	// TCPAddr is the address the TCP check dialed: the server, or its first jump host.
+	TCPAddr    string
+	TCPLatency time.Duration
+	TCPErr     error
+
+	SSHLatency time.Duration
+	SSHErr     error
+
+	// The Docker fields cover whichever container runtime the server uses.
+	DockerChecked    bool
+	DockerInstalled  bool
+	DockerContainers int
+	DockerErr        error
+}
+
+// OK reports whether every check that ran passed. A server without a container runtime is still OK.
+func (h Health) OK() bool {
+	return h.TCPErr == nil && h.SSHErr == nil && h.DockerErr == nil
+}
+
+// CheckHealth checks TCP reachability, SSH authentication and container runtime availability on server.
+func CheckHealth(server models.Server) Health {
+	var health Health
+
+	// 점프 호스트 뒤의 서버는 직접 연결할 수 없으므로 첫 홉까지의 TCP를 확인
+	first := server
+	hops, err := jumpHops(server)
+	if err != nil {
+		health.TCPErr = err
+		return health
+	}
+	if len(hops) > 0 {
+		first = hops[0]
+	}
+	health.TCPAddr = serverAddr(first)
+
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", health.TCPAddr, connectTimeout)
+	if err != nil {
+		health.TCPErr = err
+		return health
+	}
+	health.TCPLatency = time.Since(start)
+	conn.Close()
+
+	start = time.Now()
+	if _, err := ExecuteRemoteCommand(server, "true"); err != nil {
+		health.SSHErr = err
+		return health
+	}
+	health.SSHLatency = time.Since(start)
+
+	health.DockerChecked = true
+	output, err := ExecuteRemoteCommand(server, fmt.Sprintf(
+		`if %s; then ids=$("$rt" ps -q) && echo "$ids" | awk NF | wc -l; else echo missing; fi`, runtimeProbe(server)))
+	switch {
+	case err != nil:
+		health.DockerInstalled = true
+		health.DockerErr = errors.New(lastLine(err.Error()))
+	case output != "missing":
+		health.DockerInstalled = true
+		health.DockerContainers, _ = strconv.Atoi(lastLine(output))
+	}
+	return health
+}
+
+func lastLine(s string) string {
+	s = strings.TrimSpace(s)
+	return s[strings.LastIndex(s, "\n")+1:]
+}
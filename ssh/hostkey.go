@@ -0,0 +1,337 @@
+package ssh
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"remotelink/models"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Host key policies accepted by the host_key_policy server field.
+const (
+	HostKeyStrict = "strict" // only hosts already in the store are accepted
+	HostKeyTOFU   = "tofu"   // unknown hosts are confirmed once, then pinned
+	HostKeyOff    = "off"    // no verification at all
+)
+
+// ErrHostKeyUnknown is returned when a host is not in the store and the policy does not allow trusting it.
+var ErrHostKeyUnknown = errors.New("host key is not trusted")
+
+// ConfirmHostKey asks the user whether to trust an unknown host key.
+// It is set by the cmd package; when nil, unknown hosts are rejected.
+var ConfirmHostKey func(host, fingerprint string) (bool, error)
+
+// storeMu serializes reads and writes of the known_hosts store, including the confirm prompt.
+var storeMu sync.Mutex
+
+// errKeyScanned aborts a handshake once the host key has been captured.
+var errKeyScanned = errors.New("host key scanned")
+
+// KnownHost is a single entry of the remotelink known_hosts store.
+type KnownHost struct {
+	Hosts       []string
+	KeyType     string
+	Fingerprint string
+}
+
+// KnownHostsPath returns the location of remotelink's own known_hosts file.
+func KnownHostsPath() string {
+	home, _ := homedir.Dir()
+	return filepath.Join(home, ".remotelink", "known_hosts")
+}
+
+// ValidateHostKeyPolicy reports whether policy is a known host key policy.
+func ValidateHostKeyPolicy(policy string) error {
+	switch policy {
+	case HostKeyStrict, HostKeyTOFU, HostKeyOff:
+		return nil
+	}
+	return fmt.Errorf("unknown host key policy %q (expected %q, %q or %q)", policy, HostKeyStrict, HostKeyTOFU, HostKeyOff)
+}
+
+func hostKeyPolicy(server models.Server) string {
+	if server.HostKeyPolicy == "" {
+		return HostKeyTOFU
+	}
+	return server.HostKeyPolicy
+}
+
+// hostKeyCallback verifies host keys against the store according to the server's policy.
+func hostKeyCallback(server models.Server) gossh.HostKeyCallback {
+	policy := hostKeyPolicy(server)
+	if policy == HostKeyOff {
+		return gossh.InsecureIgnoreHostKey()
+	}
+
+	return func(hostname string, remote net.Addr, key gossh.PublicKey) error {
+		storeMu.Lock()
+		defer storeMu.Unlock()
+
+		err := checkKnownHost(hostname, remote, key)
+		if !errors.Is(err, ErrHostKeyUnknown) || policy == HostKeyStrict {
+			return err
+		}
+		return confirmAndAdd(hostname, key)
+	}
+}
+
+// checkKnownHost returns nil for a trusted key, ErrHostKeyUnknown or a *HostKeyMismatchError.
+func checkKnownHost(hostname string, remote net.Addr, key gossh.PublicKey) error {
+	if err := ensureStore(); err != nil {
+		return err
+	}
+	check, err := knownhosts.New(KnownHostsPath())
+	if err != nil {
+		return fmt.Errorf("failed to read known_hosts: %w", err)
+	}
+
+	err = check(hostname, remote, key)
+	var keyErr *knownhosts.KeyError
+	if !errors.As(err, &keyErr) {
+		return err
+	}
+	if len(keyErr.Want) == 0 {
+		return fmt.Errorf("%w: %s (%s); use 'remotelink hostkey pin' to trust it",
+			ErrHostKeyUnknown, hostname, gossh.FingerprintSHA256(key))
+	}
+
+	expected := make([]string, len(keyErr.Want))
+	for i, want := range keyErr.Want {
+		expected[i] = gossh.FingerprintSHA256(want.Key)
+	}
+	return &HostKeyMismatchError{
+		Host:     hostname,
+		Expected: expected,
+		Got:      gossh.FingerprintSHA256(key),
+	}
+}
+
+func confirmAndAdd(hostname string, key gossh.PublicKey) error {
+	fingerprint := gossh.FingerprintSHA256(key)
+	if ConfirmHostKey == nil {
+		return fmt.Errorf("%w: %s (%s)", ErrHostKeyUnknown, hostname, fingerprint)
+	}
+
+	ok, err := ConfirmHostKey(hostname, fingerprint)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s rejected by user", ErrHostKeyUnknown, hostname)
+	}
+	return appendKnownHost(hostname, key)
+}
+
+// ensureStore creates an empty known_hosts file so knownhosts.New can read it.
+func ensureStore() error {
+	storePath := KnownHostsPath()
+	if err := os.MkdirAll(filepath.Dir(storePath), 0700); err != nil {
+		return fmt.Errorf("failed to create remotelink directory: %w", err)
+	}
+	f, err := os.OpenFile(storePath, os.O_CREATE|os.O_RDONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create known_hosts: %w", err)
+	}
+	return f.Close()
+}
+
+func appendKnownHost(hostname string, key gossh.PublicKey) error {
+	if err := ensureStore(); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(KnownHostsPath(), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open known_hosts: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintln(f, knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key)); err != nil {
+		return fmt.Errorf("failed to write known_hosts: %w", err)
+	}
+	return nil
+}
+
+// scanHostKey performs a key exchange with the server and returns its host key without authenticating.
+// Jump hosts in front of the server are authenticated normally.
+func scanHostKey(server models.Server) (gossh.PublicKey, net.Addr, error) {
+	conn, jumps, err := dialConn(server)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeAll(jumps)
+	defer conn.Close()
+
+	var hostKey gossh.PublicKey
+	config := &gossh.ClientConfig{
+		User: server.Username,
+		HostKeyCallback: func(hostname string, remote net.Addr, key gossh.PublicKey) error {
+			hostKey = key
+			return errKeyScanned
+		},
+	}
+
+	addr := serverAddr(server)
+	timer := time.AfterFunc(handshakeTimeout, func() { conn.Close() })
+	_, _, _, err = gossh.NewClientConn(conn, addr, config)
+	timer.Stop()
+	if hostKey == nil {
+		return nil, nil, fmt.Errorf("%w: %s: %w", ErrHostUnreachable, addr, err)
+	}
+	return hostKey, conn.RemoteAddr(), nil
+}
+
+// TrustHostKey makes sure the host keys of the server and its jump hosts are in the store
+// before a non-interactive operation, prompting for them under the tofu policy.
+// Hosts that are already known are not contacted.
+func TrustHostKey(server models.Server) error {
+	hops, err := jumpHops(server)
+	if err != nil {
+		return err
+	}
+
+	for _, hop := range append(hops, server) {
+		if err := trustHop(hop); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func trustHop(server models.Server) error {
+	if hostKeyPolicy(server) == HostKeyOff {
+		return nil
+	}
+
+	known, err := isHostKnown(server)
+	if err != nil || known {
+		return err
+	}
+
+	key, remote, err := scanHostKey(server)
+	if err != nil {
+		return err
+	}
+	return hostKeyCallback(server)(serverAddr(server), remote, key)
+}
+
+// KnownHostAddress returns the server's address as it is written in known_hosts.
+func KnownHostAddress(server models.Server) string {
+	return knownhosts.Normalize(serverAddr(server))
+}
+
+func isHostKnown(server models.Server) (bool, error) {
+	entries, err := ListKnownHosts()
+	if err != nil {
+		return false, err
+	}
+	address := KnownHostAddress(server)
+	for _, entry := range entries {
+		if containsHost(entry.Hosts, address) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListKnownHosts returns every entry of the store.
+func ListKnownHosts() ([]KnownHost, error) {
+	data, err := os.ReadFile(KnownHostsPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read known_hosts: %w", err)
+	}
+
+	var entries []KnownHost
+	for len(data) > 0 {
+		_, hosts, key, _, rest, err := gossh.ParseKnownHosts(data)
+		if err != nil {
+			break
+		}
+		entries = append(entries, KnownHost{
+			Hosts:       hosts,
+			KeyType:     key.Type(),
+			Fingerprint: gossh.FingerprintSHA256(key),
+		})
+		data = rest
+	}
+	return entries, nil
+}
+
+// ForgetHostKey removes every stored key for the server and returns how many were removed.
+func ForgetHostKey(server models.Server) (int, error) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
+	return removeKnownHost(KnownHostAddress(server))
+}
+
+// PinHostKey fetches the server's current host key and stores it, replacing any previous key.
+func PinHostKey(server models.Server) (string, error) {
+	key, _, err := scanHostKey(server)
+	if err != nil {
+		return "", err
+	}
+
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
+	if _, err := removeKnownHost(KnownHostAddress(server)); err != nil {
+		return "", err
+	}
+	if err := appendKnownHost(serverAddr(server), key); err != nil {
+		return "", err
+	}
+	return gossh.FingerprintSHA256(key), nil
+}
+
+func removeKnownHost(address string) (int, error) {
+	data, err := os.ReadFile(KnownHostsPath())
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read known_hosts: %w", err)
+	}
+
+	var kept bytes.Buffer
+	removed := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		_, hosts, _, _, _, err := gossh.ParseKnownHosts([]byte(line))
+		if err == nil && containsHost(hosts, address) {
+			removed++
+			continue
+		}
+		kept.WriteString(line + "\n")
+	}
+
+	if removed == 0 {
+		return 0, nil
+	}
+	if err := os.WriteFile(KnownHostsPath(), kept.Bytes(), 0600); err != nil {
+		return 0, fmt.Errorf("failed to write known_hosts: %w", err)
+	}
+	return removed, nil
+}
+
+func containsHost(hosts []string, address string) bool {
+	for _, host := range hosts {
+		if host == address {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package ssh
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreRules matches paths against the patterns of an IgnoreFileName file and --exclude flags.
+// It understands the common subset of rsync's exclude syntax: shell globs matched against the
+// name, or against the whole path when the pattern contains a slash; a leading slash anchors
+// the pattern to the root and a trailing slash matches directories only.
+type IgnoreRules struct {
+	patterns []ignorePattern
+}
+
+type ignorePattern struct {
+	glob     string
+	anchored bool
+	dirOnly  bool
+}
+
+// LoadIgnoreRules reads root's IgnoreFileName, if any, and adds the extra patterns.
+func LoadIgnoreRules(root string, extra []string) (*IgnoreRules, error) {
+	rules := &IgnoreRules{}
+
+	f, err := os.Open(filepath.Join(root, IgnoreFileName))
+	switch {
+	case err == nil:
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			rules.add(scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	for _, pattern := range extra {
+		rules.add(pattern)
+	}
+	return rules, nil
+}
+
+func (r *IgnoreRules) add(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+	// rsync 필터 규칙의 "- " 접두사는 제외 규칙과 같음
+	line = strings.TrimPrefix(line, "- ")
+
+	var p ignorePattern
+	p.dirOnly = strings.HasSuffix(line, "/")
+	line = strings.TrimSuffix(line, "/")
+	p.anchored = strings.HasPrefix(line, "/") || strings.Contains(line, "/")
+	p.glob = strings.TrimPrefix(line, "/")
+	if p.glob != "" {
+		r.patterns = append(r.patterns, p)
+	}
+}
+
+// Match reports whether rel, a slash-separated path below the root, or any directory above it is ignored.
+// Files still being transferred by SFTP are always ignored.
+func (r *IgnoreRules) Match(rel string, isDir bool) bool {
+	if strings.HasSuffix(rel, partSuffix) {
+		return true
+	}
+	if r == nil {
+		return false
+	}
+
+	parts := strings.Split(rel, "/")
+	for i := range parts {
+		prefix := strings.Join(parts[:i+1], "/")
+		prefixIsDir := isDir || i < len(parts)-1
+		for _, p := range r.patterns {
+			if p.dirOnly && !prefixIsDir {
+				continue
+			}
+			name := parts[i]
+			if p.anchored {
+				name = prefix
+			}
+			if ok, _ := path.Match(p.glob, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
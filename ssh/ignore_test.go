@@ -0,0 +1,61 @@
+package ssh
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnoreRulesMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		rel      string
+		isDir    bool
+		want     bool
+	}{
+		{name: "no patterns", rel: "main.go", want: false},
+		{name: "part file", rel: "dir/big.iso" + partSuffix, want: true},
+		{name: "name glob", patterns: []string{"*.log"}, rel: "logs/app.log", want: true},
+		{name: "name glob miss", patterns: []string{"*.log"}, rel: "logs/app.txt", want: false},
+		{name: "directory ignores its contents", patterns: []string{"node_modules"}, rel: "web/node_modules/react/index.js", want: true},
+		{name: "dir-only pattern matches directory", patterns: []string{"build/"}, rel: "build", isDir: true, want: true},
+		{name: "dir-only pattern skips file", patterns: []string{"build/"}, rel: "build", want: false},
+		{name: "dir-only pattern matches parent", patterns: []string{"build/"}, rel: "build/out.bin", want: true},
+		{name: "anchored", patterns: []string{"/config.yaml"}, rel: "config.yaml", want: true},
+		{name: "anchored skips nested", patterns: []string{"/config.yaml"}, rel: "sub/config.yaml", want: false},
+		{name: "path pattern", patterns: []string{"docs/*.md"}, rel: "docs/readme.md", want: true},
+		{name: "path pattern is anchored", patterns: []string{"docs/*.md"}, rel: "sub/docs/readme.md", want: false},
+		{name: "rsync exclude prefix", patterns: []string{"- *.tmp"}, rel: "a.tmp", want: true},
+		{name: "comments and blanks", patterns: []string{"# *.go", "", "  "}, rel: "main.go", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := &IgnoreRules{}
+			for _, pattern := range tt.patterns {
+				rules.add(pattern)
+			}
+			if got := rules.Match(tt.rel, tt.isDir); got != tt.want {
+				t.Errorf("Match(%q, %v) = %v, want %v", tt.rel, tt.isDir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadIgnoreRules(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, IgnoreFileName), []byte("# build output\ndist/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := LoadIgnoreRules(root, []string{"*.log"})
+	if err != nil {
+		t.Fatalf("LoadIgnoreRules() error = %v", err)
+	}
+	for rel, want := range map[string]bool{"dist/app.js": true, "app.log": true, "main.go": false} {
+		if got := rules.Match(rel, false); got != want {
+			t.Errorf("Match(%q) = %v, want %v", rel, got, want)
+		}
+	}
+}
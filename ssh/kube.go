@@ -0,0 +1,155 @@
+package ssh
+
+import (
+	"fmt"
+	"remotelink/models"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// podsFormat prints one "namespace pod containers images" line per running pod, with the
+// containers and images comma-separated in the same order.
+const podsFormat = `{range .items[*]}{.metadata.namespace}{"\t"}{.metadata.name}{"\t"}` +
+	`{range .spec.containers[*]}{.name}{","}{end}{"\t"}{range .spec.containers[*]}{.image}{","}{end}{"\n"}{end}`
+
+// podRef is a parsed pod/<namespace>/<pod>[/<container>] reference.
+type podRef struct {
+	Namespace string
+	Pod       string
+	// Container is empty for the pod's default container.
+	Container string
+}
+
+// parsePodRef parses ref as a pod reference. ok is false for a plain container name.
+func parsePodRef(ref string) (pod podRef, ok bool) {
+	rest, found := strings.CutPrefix(ref, models.PodRefPrefix)
+	if !found {
+		return podRef{}, false
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return podRef{}, false
+	}
+	pod = podRef{Namespace: parts[0], Pod: parts[1]}
+	if len(parts) == 3 {
+		pod.Container = parts[2]
+	}
+	return pod, true
+}
+
+// args are the kubectl arguments that select the pod and container.
+func (p podRef) args() string {
+	args := "-n " + shellQuote(p.Namespace) + " " + shellQuote(p.Pod)
+	if p.Container != "" {
+		args += " -c " + shellQuote(p.Container)
+	}
+	return args
+}
+
+// kubectlProbe is a shell condition that sets $kc to the server's kubectl, falling back to the one
+// bundled with k3s, and exports the configured kubeconfig.
+func kubectlProbe(server models.Server) string {
+	probe := `if command -v kubectl > /dev/null 2>&1; then kc=kubectl; elif command -v k3s > /dev/null 2>&1; then kc="k3s kubectl"; else false; fi`
+	if server.Kubeconfig != "" {
+		probe = "export KUBECONFIG=" + remoteShellPath(server.Kubeconfig) + " && " + probe
+	}
+	return probe
+}
+
+// kubectlCommand prefixes commands, which call kubectl as $kc, with kubectlProbe.
+func kubectlCommand(server models.Server, commands ...string) string {
+	return kubectlProbe(server) + " && " + strings.Join(commands, " && ")
+}
+
+// FetchPods returns the containers of the running pods on the server, in every namespace.
+func FetchPods(server models.Server) ([]models.Container, error) {
+	output, err := ExecuteRemoteCommand(server, kubectlCommand(server,
+		fmt.Sprintf("$kc get pods -A --field-selector=status.phase=Running -o jsonpath=%s", shellQuote(podsFormat))))
+	if err != nil {
+		return nil, fmt.Errorf("kubectl is not available on the remote server: %w", err)
+	}
+
+	var containers []models.Container
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(strings.TrimSpace(line), "\t")
+		if len(parts) != 4 {
+			continue
+		}
+		names := strings.Split(strings.TrimSuffix(parts[2], ","), ",")
+		images := strings.Split(strings.TrimSuffix(parts[3], ","), ",")
+		for i, name := range names {
+			container := models.Container{ContainerName: name, Namespace: parts[0], Pod: parts[1]}
+			if i < len(images) {
+				container.ImageName = images[i]
+			}
+			containers = append(containers, container)
+		}
+	}
+	return containers, nil
+}
+
+// FetchTargets returns the running containers, or every container when all is set, and, where
+// kubectl works, the running pod containers on the server. It fails only when neither can be
+// listed, with the container runtime's error.
+func FetchTargets(server models.Server, all bool) ([]models.Container, error) {
+	var pods []models.Container
+	var podErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		pods, podErr = FetchPods(server)
+	}()
+
+	containers, err := FetchContainers(server, all)
+	wg.Wait()
+
+	if err != nil && podErr == nil {
+		return pods, nil
+	}
+	return append(containers, pods...), err
+}
+
+// GroupPods splits pod containers by namespace, both sorted by name.
+func GroupPods(containers []models.Container) (namespaces []string, byNamespace map[string][]models.Container) {
+	byNamespace = map[string][]models.Container{}
+	for _, container := range containers {
+		if _, seen := byNamespace[container.Namespace]; !seen {
+			namespaces = append(namespaces, container.Namespace)
+		}
+		byNamespace[container.Namespace] = append(byNamespace[container.Namespace], container)
+	}
+	sort.Strings(namespaces)
+	for _, namespace := range namespaces {
+		sort.SliceStable(byNamespace[namespace], func(i, j int) bool {
+			return byNamespace[namespace][i].Ref() < byNamespace[namespace][j].Ref()
+		})
+	}
+	return namespaces, byNamespace
+}
+
+// SplitPods separates pod containers from runtime containers.
+func SplitPods(containers []models.Container) (runtime, pods []models.Container) {
+	for _, container := range containers {
+		if container.Pod != "" {
+			pods = append(pods, container)
+		} else {
+			runtime = append(runtime, container)
+		}
+	}
+	return runtime, pods
+}
+
+// podIP returns the IP address of a running pod.
+func podIP(server models.Server, pod podRef) (string, error) {
+	output, err := ExecuteRemoteCommand(server, kubectlCommand(server,
+		fmt.Sprintf("$kc get pod -n %s %s -o jsonpath='{.status.podIP}'", shellQuote(pod.Namespace), shellQuote(pod.Pod))))
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect pod '%s': %w", pod.Pod, err)
+	}
+	if output == "" {
+		return "", fmt.Errorf("pod '%s' has no IP address", pod.Pod)
+	}
+	return output, nil
+}
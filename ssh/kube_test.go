@@ -0,0 +1,46 @@
+package ssh
+
+import "testing"
+
+func TestParsePodRef(t *testing.T) {
+	tests := []struct {
+		ref    string
+		want   podRef
+		wantOK bool
+	}{
+		{ref: "pod/default/web-7d9f", want: podRef{Namespace: "default", Pod: "web-7d9f"}, wantOK: true},
+		{ref: "pod/prod/api-0/sidecar", want: podRef{Namespace: "prod", Pod: "api-0", Container: "sidecar"}, wantOK: true},
+		{ref: "pod/prod/api-0/", want: podRef{Namespace: "prod", Pod: "api-0"}, wantOK: true},
+		{ref: "nginx"},
+		{ref: "pod/default"},
+		{ref: "pod//web"},
+		{ref: "pod/default/"},
+		{ref: "pod/a/b/c/d"},
+		{ref: "pods/default/web"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, ok := parsePodRef(tt.ref)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("parsePodRef(%q) = %+v, %v, want %+v, %v", tt.ref, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPodRefArgs(t *testing.T) {
+	tests := []struct {
+		pod  podRef
+		want string
+	}{
+		{podRef{Namespace: "default", Pod: "web"}, "-n 'default' 'web'"},
+		{podRef{Namespace: "prod", Pod: "api-0", Container: "sidecar"}, "-n 'prod' 'api-0' -c 'sidecar'"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.pod.args(); got != tt.want {
+			t.Errorf("args() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"remotelink/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// streamStopTimeout bounds how long a stopped stream is waited for to end.
+const streamStopTimeout = 5 * time.Second
+
+// LogSource is where logs are read from: a container, a file on the host, a systemd unit
+// or the whole journal. Exactly one field is set.
+type LogSource struct {
+	Container string
+	File      string
+	Unit      string
+	Journal   bool
+}
+
+// systemLogFiles are read when the journal has nothing for the user, in order of preference.
+var systemLogFiles = []string{"/var/log/syslog", "/var/log/messages"}
+
+// SystemLogSource picks where the host's system log can be read from: the journal when it
+// shows the user any entries, and otherwise the first readable syslog file. journalctl exits
+// 0 with no entries for a user outside the systemd-journal group, so its exit status alone
+// cannot tell.
+func SystemLogSource(server models.Server) (LogSource, error) {
+	probe := `[ -n "$(journalctl -q -n 1 2>/dev/null)" ] && echo journal`
+	for _, file := range systemLogFiles {
+		probe += fmt.Sprintf(" || { [ -r %s ] && echo %s; }", file, file)
+	}
+	output, err := ExecuteRemoteCommand(server, probe+" || true")
+	if err != nil {
+		return LogSource{}, err
+	}
+
+	switch found := strings.TrimSpace(output); found {
+	case "":
+		return LogSource{}, fmt.Errorf("no readable system log on %s (the journal is empty for %s and %s are not readable)",
+			server.ServerName, server.Username, strings.Join(systemLogFiles, ", "))
+	case "journal":
+		return LogSource{Journal: true}, nil
+	default:
+		return LogSource{File: found}, nil
+	}
+}
+
+// LogOptions narrows what StreamLogs prints.
+type LogOptions struct {
+	// Since is a duration such as "10m" or a timestamp; files do not support it.
+	Since string
+	// Tail is the number of lines to start with, or "all".
+	Tail   string
+	Follow bool
+}
+
+// ValidateTail reports whether tail is "all" or a line count.
+func ValidateTail(tail string) error {
+	if tail == "all" {
+		return nil
+	}
+	if n, err := strconv.Atoi(tail); err != nil || n < 0 {
+		return fmt.Errorf("invalid --tail %q (expected a line count or \"all\")", tail)
+	}
+	return nil
+}
+
+// StreamLogs copies the logs of src on the server to stdout and stderr until they end or ctx is
+// cancelled. Cancelling kills the remote process too, so `--follow` does not outlive the command.
+func StreamLogs(ctx context.Context, server models.Server, src LogSource, opts LogOptions, stdout, stderr io.Writer) error {
+	command, err := logCommand(server, src, opts)
+	if err != nil {
+		return err
+	}
+	return streamCommand(ctx, server, command, nil, stdout, stderr)
+}
+
+// streamCommand runs command on the server with stdin, copying its output to stdout and stderr,
+// until it ends or ctx is cancelled. Cancelling kills the remote shell and its process group too,
+// so nothing it started outlives the stream, and waits at most streamStopTimeout for it to end.
+func streamCommand(ctx context.Context, server models.Server, command string, stdin io.Reader, stdout, stderr io.Writer) error {
+	// 첫 줄로 원격 셸의 PID를 받고, exec된 명령이 같은 PID를 이어받음
+	pid := &pidWriter{out: stdout, ready: make(chan struct{})}
+	done := make(chan error, 1)
+	go func() {
+		done <- BackendFor(server).Stream(server, "echo $$; "+command, stdin, pid, stderr)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+
+	// 연결이 먼저 끊겨도 원격 프로세스가 남지 않도록 별도 연결로 종료
+	// sshd가 만든 셸은 프로세스 그룹의 리더이므로 그룹 전체를 먼저 시도
+	select {
+	case <-pid.ready:
+		ExecuteRemoteCommand(server, fmt.Sprintf("kill -- -%s 2>/dev/null || kill %s", pid.pid, pid.pid))
+	default:
+	}
+	select {
+	case <-done:
+	case <-time.After(streamStopTimeout):
+	}
+	return ctx.Err()
+}
+
+// logCommand builds the remote command that prints the logs of src, exec-ing the log reader
+// so it keeps the shell's PID.
+func logCommand(server models.Server, src LogSource, opts LogOptions) (string, error) {
+	if err := ValidateTail(opts.Tail); err != nil {
+		return "", err
+	}
+
+	var args []string
+	switch {
+	case src.Container != "":
+		if pod, ok := parsePodRef(src.Container); ok {
+			return podLogCommand(server, pod, opts), nil
+		}
+		args = []string{`"$rt"`, "logs", "--tail", opts.Tail}
+		if opts.Since != "" {
+			args = append(args, "--since", shellQuote(opts.Since))
+		}
+		if opts.Follow {
+			args = append(args, "-f")
+		}
+		args = append(args, shellQuote(src.Container))
+		return runtimeCommand(server, "exec "+strings.Join(args, " ")), nil
+
+	case src.File != "":
+		if opts.Since != "" {
+			return "", fmt.Errorf("--since is not supported for log files")
+		}
+		lines := opts.Tail
+		if lines == "all" {
+			lines = "+1"
+		}
+		args = []string{"tail", "-n", lines}
+		if opts.Follow {
+			args = append(args, "-F")
+		}
+		args = append(args, remoteShellPath(src.File))
+
+	case src.Unit != "" || src.Journal:
+		args = []string{"journalctl", "--no-pager", "-n", opts.Tail}
+		if src.Unit != "" {
+			args = append(args, "-u", shellQuote(src.Unit))
+		}
+		if opts.Since != "" {
+			since := opts.Since
+			// journalctl은 "10m"이 아니라 "-10m" 형식의 상대 시간을 받음
+			if _, err := time.ParseDuration(since); err == nil {
+				since = "-" + since
+			}
+			args = append(args, "--since", shellQuote(since))
+		}
+		if opts.Follow {
+			args = append(args, "-f")
+		}
+
+	default:
+		return "", fmt.Errorf("no log source given")
+	}
+	return "exec " + strings.Join(args, " "), nil
+}
+
+// podLogCommand is logCommand for a pod, where a Since that is not a duration is a timestamp.
+func podLogCommand(server models.Server, pod podRef, opts LogOptions) string {
+	tail := opts.Tail
+	if tail == "all" {
+		tail = "-1"
+	}
+	args := []string{"$kc", "logs", pod.args(), "--tail", tail}
+	if opts.Since != "" {
+		if _, err := time.ParseDuration(opts.Since); err == nil {
+			args = append(args, "--since", shellQuote(opts.Since))
+		} else {
+			args = append(args, "--since-time", shellQuote(opts.Since))
+		}
+	}
+	if opts.Follow {
+		args = append(args, "-f")
+	}
+	return kubectlCommand(server, "exec "+strings.Join(args, " "))
+}
+
+// pidWriter takes the first line of output as the remote PID and passes the rest on to out.
+type pidWriter struct {
+	out   io.Writer
+	ready chan struct{}
+	buf   []byte
+	pid   string
+}
+
+func (w *pidWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if w.pid == "" {
+		w.buf = append(w.buf, p...)
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return n, nil
+		}
+		w.pid = strings.TrimSpace(string(w.buf[:i]))
+		close(w.ready)
+		p = w.buf[i+1:]
+		w.buf = nil
+	}
+	if w.out == nil || len(p) == 0 {
+		return n, nil
+	}
+	if _, err := w.out.Write(p); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
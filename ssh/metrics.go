@@ -0,0 +1,98 @@
+package ssh
+
+import (
+	"fmt"
+	"remotelink/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metrics is a snapshot of a server's load, memory, root disk and running containers.
+type Metrics struct {
+	Load1, Load5, Load15 float64
+
+	MemTotal     uint64
+	MemAvailable uint64
+
+	DiskTotal uint64
+	DiskUsed  uint64
+
+	// Containers is the number of running containers, or -1 when no container runtime is installed.
+	Containers int
+
+	// Latency is how long the whole probe took, connection included.
+	Latency time.Duration
+}
+
+// MemUsedPercent is the share of memory not available to new processes.
+func (m Metrics) MemUsedPercent() float64 {
+	if m.MemTotal == 0 {
+		return 0
+	}
+	return 100 * float64(m.MemTotal-m.MemAvailable) / float64(m.MemTotal)
+}
+
+// DiskUsedPercent is the share of the root filesystem in use.
+func (m Metrics) DiskUsedPercent() float64 {
+	if m.DiskTotal == 0 {
+		return 0
+	}
+	return 100 * float64(m.DiskUsed) / float64(m.DiskTotal)
+}
+
+// metricsScript prints one "key values..." line per metric so missing tools only drop their line.
+func metricsScript(server models.Server) string {
+	return strings.Join([]string{
+		`printf 'load %s\n' "$(cut -d' ' -f1-3 /proc/loadavg)"`,
+		`awk '/^MemTotal:/{t=$2} /^MemAvailable:/{a=$2} END{printf "mem %.0f %.0f\n", t*1024, a*1024}' /proc/meminfo`,
+		`df -Pk / | awk 'NR==2{printf "disk %.0f %.0f\n", $2*1024, $3*1024}'`,
+		fmt.Sprintf(`if %s; then echo "containers $("$rt" ps -q 2>/dev/null | wc -l)"; fi`, runtimeProbe(server)),
+	}, "; ")
+}
+
+// FetchMetrics collects Metrics from a Linux server in a single remote command.
+func FetchMetrics(server models.Server) (Metrics, error) {
+	start := time.Now()
+	output, err := ExecuteRemoteCommand(server, metricsScript(server))
+	if err != nil {
+		return Metrics{}, err
+	}
+
+	metrics := Metrics{Containers: -1, Latency: time.Since(start)}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch values := fields[1:]; fields[0] {
+		case "load":
+			if len(values) == 3 {
+				metrics.Load1, _ = strconv.ParseFloat(values[0], 64)
+				metrics.Load5, _ = strconv.ParseFloat(values[1], 64)
+				metrics.Load15, _ = strconv.ParseFloat(values[2], 64)
+			}
+		case "mem":
+			if len(values) == 2 {
+				metrics.MemTotal = parseUint(values[0])
+				metrics.MemAvailable = parseUint(values[1])
+			}
+		case "disk":
+			if len(values) == 2 {
+				metrics.DiskTotal = parseUint(values[0])
+				metrics.DiskUsed = parseUint(values[1])
+			}
+		case "containers":
+			if len(values) == 1 {
+				metrics.Containers, _ = strconv.Atoi(values[0])
+			}
+		}
+	}
+	return metrics, nil
+}
+
+func parseUint(s string) uint64 {
+	n, _ := strconv.ParseUint(s, 10, 64)
+	return n
+}
@@ -0,0 +1,241 @@
+package ssh
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"remotelink/models"
+	"strings"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/term"
+)
+
+// nativeBackend speaks SSH in-process via golang.org/x/crypto/ssh.
+type nativeBackend struct{}
+
+func (nativeBackend) Run(server models.Server, command string) (string, error) {
+	client, err := Dial(server)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return "", fmt.Errorf("failed to open session: %w", err)
+	}
+	defer session.Close()
+
+	// 시간 초과 시 연결을 끊어 실행 중인 명령을 중단
+	timer := time.AfterFunc(commandTimeout, func() { client.Close() })
+	output, err := session.CombinedOutput(command)
+	if !timer.Stop() {
+		return "", fmt.Errorf("SSH command timed out (10s)")
+	}
+	if err != nil {
+		return "", fmt.Errorf("SSH command failed: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (nativeBackend) Interactive(server models.Server, command string) error {
+	client, err := Dial(server)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("failed to open session: %w", err)
+	}
+	defer session.Close()
+
+	session.Stdin = os.Stdin
+	session.Stdout = os.Stdout
+	session.Stderr = os.Stderr
+
+	stdinFd := int(os.Stdin.Fd())
+	stdoutFd := int(os.Stdout.Fd())
+	if term.IsTerminal(stdinFd) {
+		width, height, err := term.GetSize(stdoutFd)
+		if err != nil {
+			width, height = 80, 24
+		}
+
+		termType := os.Getenv("TERM")
+		if termType == "" {
+			termType = "xterm-256color"
+		}
+
+		modes := gossh.TerminalModes{
+			gossh.ECHO:          1,
+			gossh.TTY_OP_ISPEED: 14400,
+			gossh.TTY_OP_OSPEED: 14400,
+		}
+		if err := session.RequestPty(termType, height, width, modes); err != nil {
+			return fmt.Errorf("failed to request pty: %w", err)
+		}
+
+		oldState, err := term.MakeRaw(stdinFd)
+		if err != nil {
+			return fmt.Errorf("failed to set raw mode: %w", err)
+		}
+		defer term.Restore(stdinFd, oldState)
+
+		// 로컬 터미널 크기 변경을 원격 PTY에 전달
+		stop := watchWindowSize(stdoutFd, func(width, height int) {
+			session.WindowChange(height, width)
+		})
+		defer stop()
+	}
+
+	if command == "" {
+		if err := session.Shell(); err != nil {
+			return err
+		}
+		return session.Wait()
+	}
+	return session.Run(command)
+}
+
+func (nativeBackend) Upload(server models.Server, localPath, remotePath string) error {
+	if _, err := os.Stat(localPath); err != nil {
+		return err
+	}
+
+	client, err := Dial(server)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	destDir, name, err := remoteDestination(client, remotePath, filepath.Base(localPath))
+	if err != nil {
+		return err
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("failed to open session: %w", err)
+	}
+	defer session.Close()
+
+	var stderr bytes.Buffer
+	session.Stderr = &stderr
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	dir := remoteShellPath(destDir)
+	if err := session.Start(fmt.Sprintf("mkdir -p %s && tar -xf - -C %s", dir, dir)); err != nil {
+		return fmt.Errorf("upload failed: %w", err)
+	}
+
+	writeErr := writeTar(stdin, localPath, name)
+	stdin.Close()
+
+	if err := session.Wait(); err != nil {
+		return fmt.Errorf("upload failed: %w\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+	if writeErr != nil {
+		return fmt.Errorf("upload failed: %w", writeErr)
+	}
+	return nil
+}
+
+func (nativeBackend) Download(server models.Server, remotePath, localPath string) error {
+	client, err := Dial(server)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("failed to open session: %w", err)
+	}
+	defer session.Close()
+
+	var stderr bytes.Buffer
+	session.Stderr = &stderr
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	cleaned := path.Clean(remotePath)
+	srcName := path.Base(cleaned)
+	command := fmt.Sprintf("tar -cf - -C %s %s", remoteShellPath(path.Dir(cleaned)), shellQuote(srcName))
+	if err := session.Start(command); err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+
+	destDir, name := localDestination(localPath, srcName)
+	extractErr := extractTar(stdout, destDir, name)
+
+	if err := session.Wait(); err != nil {
+		return fmt.Errorf("download failed: %w\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+	if extractErr != nil {
+		return fmt.Errorf("download failed: %w", extractErr)
+	}
+	return nil
+}
+
+func (nativeBackend) Stream(server models.Server, command string, stdin io.Reader, stdout, stderr io.Writer) error {
+	client, err := Dial(server)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("failed to open session: %w", err)
+	}
+	defer session.Close()
+
+	var captured bytes.Buffer
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = stderr
+	if stderr == nil {
+		session.Stderr = &captured
+	}
+
+	if err := session.Run(command); err != nil {
+		return fmt.Errorf("%w\n%s", err, strings.TrimSpace(captured.String()))
+	}
+	return nil
+}
+
+// remoteDestination resolves where an upload lands, following scp semantics:
+// into remotePath when it is an existing directory, otherwise as remotePath itself.
+func remoteDestination(client *Client, remotePath, srcName string) (dir, name string, err error) {
+	if remotePath == "" || strings.HasSuffix(remotePath, "/") {
+		return remotePath, srcName, nil
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open session: %w", err)
+	}
+	defer session.Close()
+
+	output, err := session.Output(fmt.Sprintf("if [ -d %s ]; then echo dir; fi", remoteShellPath(remotePath)))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to inspect remote path: %w", err)
+	}
+	if strings.TrimSpace(string(output)) == "dir" {
+		return remotePath, srcName, nil
+	}
+	return path.Dir(remotePath), path.Base(remotePath), nil
+}
@@ -0,0 +1,303 @@
+package ssh
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"remotelink/models"
+	"strings"
+)
+
+// Transfer methods accepted by the transfer server field and the --transfer flag.
+const (
+	// TransferSFTP copies everything over SFTP with progress, resume and verification.
+	TransferSFTP = "sftp"
+	// TransferSCP copies everything through the SSH backend: scp for exec, a tar stream for native.
+	TransferSCP = "scp"
+	// TransferRsync runs rsync over ssh and only sends what changed.
+	TransferRsync = "rsync"
+)
+
+// DefaultTransfer is used for servers that do not set transfer.
+var DefaultTransfer = TransferSFTP
+
+// IgnoreFileName is read from the root of the local side of an rsync transfer, in rsync filter syntax.
+const IgnoreFileName = ".remotelinkignore"
+
+// Change kinds reported by TransferOptions.Change.
+const (
+	ChangeCreate = "create"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// TransferChange is one path rsync created, updated or deleted (or would have, on a dry run).
+type TransferChange struct {
+	Kind string
+	Path string
+}
+
+// TransferOptions tunes Upload and Download. Delete, Excludes and DryRun need rsync;
+// Checksum works with rsync and SFTP.
+type TransferOptions struct {
+	// Method overrides the server's transfer field.
+	Method string
+
+	// Delete removes destination files that no longer exist in the source.
+	Delete bool
+	// Excludes are rsync patterns added to those in IgnoreFileName.
+	Excludes []string
+	// Checksum compares file contents instead of size and modification time with rsync,
+	// and verifies every file by SHA-256 with SFTP.
+	Checksum bool
+	// DryRun reports changes through Change without transferring anything.
+	DryRun bool
+
+	// Change, when set, is called for every path rsync touches.
+	Change func(TransferChange)
+	// Progress, when set, is called as SFTP transfers advance and once per completed file.
+	Progress func(TransferProgress)
+}
+
+func (o TransferOptions) needsRsync() bool {
+	return o.Delete || len(o.Excludes) > 0 || o.DryRun
+}
+
+// ValidateTransfer reports whether name is a known transfer method.
+func ValidateTransfer(name string) error {
+	switch name {
+	case TransferSFTP, TransferSCP, TransferRsync:
+		return nil
+	}
+	return fmt.Errorf("unknown transfer method %q (expected %q, %q or %q)", name, TransferSFTP, TransferSCP, TransferRsync)
+}
+
+// transferMethod picks the method for a transfer: the option, then the server field, then DefaultTransfer.
+// rsync-only options select rsync unless another method was asked for explicitly.
+func transferMethod(server models.Server, opts TransferOptions) (string, error) {
+	method := opts.Method
+	if method == "" {
+		method = server.Transfer
+	}
+	if method == "" && opts.needsRsync() {
+		method = TransferRsync
+	}
+	if method == "" {
+		method = DefaultTransfer
+	}
+	if err := ValidateTransfer(method); err != nil {
+		return "", err
+	}
+	if method != TransferRsync && opts.needsRsync() {
+		return "", fmt.Errorf("--delete, --exclude and --dry-run need the rsync transfer method")
+	}
+	if method == TransferSCP && opts.Checksum {
+		return "", fmt.Errorf("--checksum needs the rsync or sftp transfer method")
+	}
+	return method, nil
+}
+
+// rsyncProbe reports whether rsync is installed on the server and whether remotePath is a directory.
+func rsyncProbe(server models.Server, remotePath string) (installed, isDir bool, err error) {
+	if _, err := exec.LookPath("rsync"); err != nil {
+		return false, false, nil
+	}
+
+	output, err := ExecuteRemoteCommand(server, fmt.Sprintf(
+		"if command -v rsync >/dev/null 2>&1; then echo rsync; fi; if [ -d %s ]; then echo dir; fi",
+		remoteShellPath(remotePath)))
+	if err != nil {
+		return false, false, fmt.Errorf("failed to inspect remote path: %w", err)
+	}
+	for _, line := range strings.Split(output, "\n") {
+		switch strings.TrimSpace(line) {
+		case "rsync":
+			installed = true
+		case "dir":
+			isDir = true
+		}
+	}
+	return installed, isDir, nil
+}
+
+// rsyncFallback explains why a transfer is copied in full instead, or fails when the options cannot be honored.
+func rsyncFallback(server models.Server, opts TransferOptions) error {
+	// 제외 규칙을 무시하고 전부 복사하면 비밀 파일 등이 올라갈 수 있으므로 중단
+	if opts.needsRsync() {
+		return fmt.Errorf("--delete, --exclude and --dry-run need rsync installed locally and on %s", server.ServerName)
+	}
+	fmt.Fprintf(os.Stderr, "rsync is not available locally or on %s; copying everything over SFTP\n", server.ServerName)
+	return nil
+}
+
+// rsyncUpload mirrors scp semantics: an existing directory receives the source by name,
+// anything else is the destination itself.
+func rsyncUpload(ctx context.Context, server models.Server, localPath, remotePath string, opts TransferOptions) (bool, error) {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return false, err
+	}
+
+	installed, isDir, err := rsyncProbe(server, remotePath)
+	if err != nil || !installed {
+		return false, err
+	}
+
+	destDir, name := path.Dir(remotePath), path.Base(remotePath)
+	if remotePath == "" || strings.HasSuffix(remotePath, "/") || isDir {
+		destDir, name = remotePath, filepath.Base(localPath)
+	}
+	if !opts.DryRun {
+		// rsync은 마지막 경로 요소만 만들 수 있으므로 상위 디렉터리를 미리 생성
+		if _, err := ExecuteRemoteCommand(server, "mkdir -p "+remoteShellPath(destDir)); err != nil {
+			return false, fmt.Errorf("failed to create remote directory: %w", err)
+		}
+	}
+
+	src, dest := localPath, path.Join(homeRelativePath(destDir), name)
+	ignoreRoot := filepath.Dir(localPath)
+	if info.IsDir() {
+		// 끝의 /는 디렉터리 자체가 아닌 내용을 동기화하라는 의미
+		src, dest = strings.TrimSuffix(localPath, string(filepath.Separator))+"/", dest+"/"
+		ignoreRoot = localPath
+	}
+
+	return true, runRsync(ctx, server, opts, ignoreRoot, src, rsyncRemoteSpec(server, dest))
+}
+
+// rsyncDownload is the reverse of rsyncUpload, using the same rules as the scp download.
+func rsyncDownload(ctx context.Context, server models.Server, remotePath, localPath string, opts TransferOptions) (bool, error) {
+	cleaned := path.Clean(remotePath)
+	installed, isDir, err := rsyncProbe(server, cleaned)
+	if err != nil || !installed {
+		return false, err
+	}
+
+	destDir, name := localDestination(localPath, path.Base(cleaned))
+	if !opts.DryRun {
+		if err := os.MkdirAll(destDir, 0o755); err != nil {
+			return false, err
+		}
+	}
+
+	src, dest := homeRelativePath(cleaned), filepath.Join(destDir, name)
+	ignoreRoot := destDir
+	if isDir {
+		src, dest = src+"/", dest+string(filepath.Separator)
+		ignoreRoot = filepath.Join(destDir, name)
+	}
+
+	return true, runRsync(ctx, server, opts, ignoreRoot, rsyncRemoteSpec(server, src), dest)
+}
+
+// runRsync transfers src to dest over ssh, reporting itemized changes through opts.Change.
+func runRsync(ctx context.Context, server models.Server, opts TransferOptions, ignoreRoot, src, dest string) error {
+	remoteShell, err := rsyncRemoteShell(server)
+	if err != nil {
+		return err
+	}
+
+	// -s는 원격 셸이 경로를 다시 해석하지 않도록 인자를 그대로 전달
+	args := []string{"-a", "-z", "-s", "--itemize-changes", "-e", remoteShell}
+	if opts.Delete {
+		args = append(args, "--delete")
+	}
+	if opts.Checksum {
+		args = append(args, "--checksum")
+	}
+	if opts.DryRun {
+		args = append(args, "--dry-run")
+	}
+	ignoreFile := filepath.Join(ignoreRoot, IgnoreFileName)
+	if _, err := os.Stat(ignoreFile); err == nil {
+		args = append(args, "--exclude-from="+ignoreFile)
+	}
+	for _, pattern := range opts.Excludes {
+		args = append(args, "--exclude="+pattern)
+	}
+	args = append(args, src, dest)
+
+	cmd := exec.CommandContext(ctx, "rsync", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("rsync failed: %w", err)
+	}
+
+	scanErr := scanItemized(stdout, opts.Change)
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("rsync failed: %w", err)
+	}
+	return scanErr
+}
+
+// scanItemized parses rsync --itemize-changes output, e.g. ">f+++++++++ app/main.js" or "*deleting   old.txt".
+// Attribute-only changes (a leading '.') and the transfer root itself are skipped.
+func scanItemized(r io.Reader, report func(TransferChange)) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if report == nil {
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(line, "*deleting"); ok {
+			report(TransferChange{Kind: ChangeDelete, Path: strings.TrimSpace(rest)})
+			continue
+		}
+		code, name, ok := strings.Cut(line, " ")
+		// 첫 글자는 갱신 종류(<>ch.), 둘째는 파일 종류(fdLDS); 그 외 줄은 rsync 메시지
+		if !ok || len(code) < 3 || !strings.ContainsRune("<>ch", rune(code[0])) || !strings.ContainsRune("fdLDS", rune(code[1])) || name == "./" {
+			continue
+		}
+
+		kind := ChangeUpdate
+		if strings.Trim(code[2:], "+") == "" {
+			kind = ChangeCreate
+		}
+		report(TransferChange{Kind: kind, Path: name})
+	}
+	return scanner.Err()
+}
+
+// rsyncRemoteShell builds the -e command with the same options as the exec backend's ssh.
+// rsync splits it on spaces and understands single quotes, where a doubled quote stands for a literal one.
+func rsyncRemoteShell(server models.Server) (string, error) {
+	sshArgs, err := buildSSHArgs(server)
+	if err != nil {
+		return "", err
+	}
+	sshArgs = append(sshArgs, hostKeyOptions(server, false)...)
+	sshArgs = append(sshArgs, "-o", "BatchMode=yes")
+
+	parts := []string{"ssh"}
+	for _, arg := range sshArgs {
+		parts = append(parts, "'"+strings.ReplaceAll(arg, "'", "''")+"'")
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// homeRelativePath turns a ~ path into one relative to the login directory,
+// for rsync -s and SFTP, which do not expand ~.
+func homeRelativePath(p string) string {
+	switch {
+	case p == "" || p == "~":
+		return "."
+	case strings.HasPrefix(p, "~/"):
+		return p[2:]
+	}
+	return p
+}
+
+func rsyncRemoteSpec(server models.Server, p string) string {
+	return fmt.Sprintf("%s@%s:%s", server.Username, server.HostIp, p)
+}
@@ -0,0 +1,64 @@
+package ssh
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScanItemized(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []TransferChange
+	}{
+		{
+			name:   "new file",
+			output: ">f+++++++++ app/main.js\n",
+			want:   []TransferChange{{Kind: ChangeCreate, Path: "app/main.js"}},
+		},
+		{
+			name:   "updated file",
+			output: ">f.st...... app/main.js\n",
+			want:   []TransferChange{{Kind: ChangeUpdate, Path: "app/main.js"}},
+		},
+		{
+			name:   "new directory",
+			output: "cd+++++++++ app/\n",
+			want:   []TransferChange{{Kind: ChangeCreate, Path: "app/"}},
+		},
+		{
+			name:   "deleted",
+			output: "*deleting   old.txt\n",
+			want:   []TransferChange{{Kind: ChangeDelete, Path: "old.txt"}},
+		},
+		{
+			name:   "name with spaces",
+			output: "<f+++++++++ my file.txt\n",
+			want:   []TransferChange{{Kind: ChangeCreate, Path: "my file.txt"}},
+		},
+		{
+			name:   "attribute-only change and root are skipped",
+			output: ".d..t...... ./\n.f...p..... a.txt\ncd..t...... ./\n",
+		},
+		{
+			name:   "unrelated lines are skipped",
+			output: "sending incremental file list\n\nsent 1 bytes\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []TransferChange
+			err := scanItemized(strings.NewReader(tt.output), func(change TransferChange) {
+				got = append(got, change)
+			})
+			if err != nil {
+				t.Fatalf("scanItemized() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scanItemized() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
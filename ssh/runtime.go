@@ -0,0 +1,178 @@
+package ssh
+
+import (
+	"fmt"
+	"path"
+	"remotelink/models"
+	"slices"
+	"strings"
+)
+
+// Container runtimes accepted by the container_runtime server field. All of them take
+// docker-compatible arguments.
+const (
+	RuntimeDocker  = "docker"
+	RuntimePodman  = "podman"
+	RuntimeNerdctl = "nerdctl"
+)
+
+// Runtimes lists the container runtimes in auto-detection order.
+var Runtimes = []string{RuntimeDocker, RuntimePodman, RuntimeNerdctl}
+
+// ValidateRuntime reports whether name is a known container runtime.
+func ValidateRuntime(name string) error {
+	for _, runtime := range Runtimes {
+		if name == runtime {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown container runtime %q (expected one of %s)", name, strings.Join(Runtimes, ", "))
+}
+
+// runtimeProbe is a shell condition that sets $rt to the server's container CLI and fails when it
+// is not installed: the configured container_runtime, or the first of Runtimes found.
+// Detecting on the server keeps every container command to a single round trip.
+func runtimeProbe(server models.Server) string {
+	if server.ContainerRuntime != "" {
+		return fmt.Sprintf("rt=%s && command -v \"$rt\" > /dev/null 2>&1", server.ContainerRuntime)
+	}
+	lookups := make([]string, len(Runtimes))
+	for i, runtime := range Runtimes {
+		lookups[i] = "command -v " + runtime
+	}
+	return fmt.Sprintf("rt=$(%s)", strings.Join(lookups, " || "))
+}
+
+// runtimeCommand prefixes commands, which call the runtime as "$rt", with runtimeProbe.
+func runtimeCommand(server models.Server, commands ...string) string {
+	return runtimeProbe(server) + " && " + strings.Join(commands, " && ")
+}
+
+// runtimeName describes the runtime a server is expected to have, for error messages.
+func runtimeName(server models.Server) string {
+	if server.ContainerRuntime != "" {
+		return server.ContainerRuntime
+	}
+	return "A container runtime (docker, podman or nerdctl)"
+}
+
+// containerTarget runs commands inside a container: through the container runtime, or through
+// kubectl when the reference names a pod.
+type containerTarget struct {
+	server models.Server
+	ref    string
+	pod    podRef
+	isPod  bool
+}
+
+// targetFor resolves a container reference as accepted after "server/".
+func targetFor(server models.Server, ref string) containerTarget {
+	pod, isPod := parsePodRef(ref)
+	return containerTarget{server: server, ref: ref, pod: pod, isPod: isPod}
+}
+
+// command prefixes commands with the probe for the runtime or kubectl they call.
+func (t containerTarget) command(commands ...string) string {
+	if t.isPod {
+		return kubectlCommand(t.server, commands...)
+	}
+	return runtimeCommand(t.server, commands...)
+}
+
+// exec is the prefix that runs a program inside the container. stdin passes standard input
+// through and tty allocates a terminal. extra are further runtime exec flags such as -u, which
+// kubectl does not take.
+func (t containerTarget) exec(stdin, tty bool, extra ...string) string {
+	flags := ""
+	if stdin {
+		flags += " -i"
+	}
+	if tty {
+		flags += " -t"
+	}
+	if t.isPod {
+		return "$kc exec" + flags + " " + t.pod.args() + " --"
+	}
+	for _, flag := range extra {
+		flags += " " + flag
+	}
+	return `"$rt" exec` + flags + " " + shellQuote(t.ref)
+}
+
+// copyOut writes p inside the container to stdout as a tar stream holding p under its base name,
+// like `docker cp <container>:<path> -`. nerdctl and kubectl cannot copy to stdout, so the
+// container's own tar is used there instead.
+func (t containerTarget) copyOut(p string) string {
+	tar := fmt.Sprintf("%s tar -cf - -C %s %s", t.exec(false, false), shellQuote(path.Dir(p)), shellQuote(path.Base(p)))
+	if t.isPod {
+		return tar
+	}
+	return fmt.Sprintf(`case "$rt" in *nerdctl) %s;; *) "$rt" cp %s -;; esac`, tar, shellQuote(t.ref+":"+p))
+}
+
+// copyIn extracts a tar stream from stdin into dir inside the container, like `docker cp - <container>:<dir>`.
+func (t containerTarget) copyIn(dir string) string {
+	tar := fmt.Sprintf("%s tar -xf - -C %s", t.exec(true, false), shellQuote(dir))
+	if t.isPod {
+		return tar
+	}
+	return fmt.Sprintf(`case "$rt" in *nerdctl) %s;; *) "$rt" cp - %s;; esac`, tar, shellQuote(t.ref+":"+dir))
+}
+
+// ContainerShellCommand returns the command that opens an interactive shell in a container or pod,
+// preferring bash.
+func ContainerShellCommand(server models.Server, container string) string {
+	t := targetFor(server, container)
+	shell := t.exec(true, true)
+	return t.command(fmt.Sprintf("{ %s /bin/bash || %s /bin/sh; }", shell, shell))
+}
+
+// ExecOptions adjust how ContainerExecCommand runs a command, like the docker exec flags.
+type ExecOptions struct {
+	User    string
+	Workdir string
+	// Env holds KEY=VALUE pairs to set in the container.
+	Env []string
+	// TTY allocates a terminal in the container, which should only be done when stdin is one.
+	TTY bool
+}
+
+// ContainerExecCommand returns the command that runs args in a container or pod with stdin
+// passed through, exiting with the command's own status.
+func ContainerExecCommand(server models.Server, container string, args []string, opts ExecOptions) (string, error) {
+	t := targetFor(server, container)
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+
+	if t.isPod {
+		// kubectl exec에는 환경 변수와 작업 디렉터리 옵션이 없어 컨테이너 안에서 설정
+		if opts.User != "" {
+			return "", fmt.Errorf("--user is not supported for pods")
+		}
+		var wrap []string
+		if len(opts.Env) > 0 {
+			wrap = append(wrap, "env")
+			for _, env := range opts.Env {
+				wrap = append(wrap, shellQuote(env))
+			}
+		}
+		if opts.Workdir != "" {
+			wrap = append(wrap, "sh", "-c", shellQuote(`cd "$0" && exec "$@"`), shellQuote(opts.Workdir))
+		}
+		return t.command(strings.Join(slices.Concat([]string{t.exec(true, opts.TTY)}, wrap, quoted), " ")), nil
+	}
+
+	var flags []string
+	if opts.User != "" {
+		flags = append(flags, "-u", shellQuote(opts.User))
+	}
+	if opts.Workdir != "" {
+		flags = append(flags, "-w", shellQuote(opts.Workdir))
+	}
+	for _, env := range opts.Env {
+		flags = append(flags, "-e", shellQuote(env))
+	}
+	return t.command(t.exec(true, opts.TTY, flags...) + " " + strings.Join(quoted, " ")), nil
+}
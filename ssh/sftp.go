@@ -0,0 +1,569 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"remotelink/models"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+)
+
+// partSuffix marks a file still being transferred. A later transfer resumes from its size.
+const partSuffix = ".remotelink-part"
+
+// progressInterval limits how often TransferOptions.Progress is called for one file.
+const progressInterval = 100 * time.Millisecond
+
+// errSFTPUnavailable means the server has no sftp subsystem, so the transfer falls back to scp.
+var errSFTPUnavailable = errors.New("sftp subsystem not available")
+
+// TransferProgress describes an SFTP transfer in flight.
+type TransferProgress struct {
+	// Path is the file being transferred, relative to the destination directory.
+	Path string
+	Size int64
+	// Done counts the bytes of Path transferred so far, including a resumed prefix.
+	Done int64
+	// Resumed is the offset Path was resumed from, or 0.
+	Resumed int64
+	// Verified is set once Path is complete: "size" or "sha256".
+	Verified string
+
+	TotalSize int64
+	TotalDone int64
+}
+
+// sftpEntry is one file or directory of a transfer, with its path relative to the source.
+type sftpEntry struct {
+	rel   string
+	isDir bool
+	size  int64
+	mode  os.FileMode
+}
+
+// openSFTP starts an SFTP session over the server's backend:
+// in-process for native, `ssh -s <host> sftp` for exec.
+func openSFTP(server models.Server) (*sftp.Client, func(), error) {
+	if _, ok := BackendFor(server).(execBackend); ok {
+		return openSFTPExec(server)
+	}
+
+	client, err := Dial(server)
+	if err != nil {
+		return nil, nil, err
+	}
+	sc, err := sftp.NewClient(client.Client)
+	if err != nil {
+		client.Close()
+		return nil, nil, fmt.Errorf("%w: %v", errSFTPUnavailable, err)
+	}
+	return sc, func() { sc.Close(); client.Close() }, nil
+}
+
+func openSFTPExec(server models.Server) (*sftp.Client, func(), error) {
+	sshArgs, err := buildSSHArgs(server)
+	if err != nil {
+		return nil, nil, err
+	}
+	sshArgs = append(sshArgs, hostKeyOptions(server, false)...)
+	sshArgs = append(sshArgs,
+		"-o", "ConnectTimeout=5",
+		"-o", "BatchMode=yes",
+		"-s", fmt.Sprintf("%s@%s", server.Username, server.HostIp), "sftp",
+	)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("ssh", sshArgs...)
+	cmd.Stderr = &stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+
+	sc, err := sftp.NewClientPipe(stdout, stdin)
+	if err != nil {
+		// ssh가 stderr를 다 쓰고 종료할 때까지 잠시 기다린 뒤 원인을 구분
+		stdin.Close()
+		done := make(chan struct{})
+		go func() { cmd.Wait(); close(done) }()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			cmd.Process.Kill()
+			<-done
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if sftpSubsystemRefused(msg) {
+			return nil, nil, fmt.Errorf("%w: %s", errSFTPUnavailable, msg)
+		}
+		if msg != "" {
+			return nil, nil, fmt.Errorf("ssh to %s failed: %s", server.ServerName, msg)
+		}
+		return nil, nil, fmt.Errorf("ssh to %s failed: %w", server.ServerName, err)
+	}
+	return sc, func() { sc.Close(); cmd.Wait() }, nil
+}
+
+// sftpSubsystemRefused reports whether ssh's stderr says the server has no usable sftp subsystem,
+// as opposed to ssh failing to connect or authenticate.
+func sftpSubsystemRefused(stderr string) bool {
+	msg := strings.ToLower(stderr)
+	if strings.Contains(msg, "subsystem") && strings.Contains(msg, "failed") {
+		return true
+	}
+	return strings.Contains(msg, "sftp-server") &&
+		(strings.Contains(msg, "not found") || strings.Contains(msg, "no such file"))
+}
+
+// sftpFallback warns that a transfer goes through scp instead, or fails when --checksum cannot be honored.
+func sftpFallback(server models.Server, opts TransferOptions) error {
+	if opts.Checksum {
+		return fmt.Errorf("--checksum needs SFTP or rsync, and %s has neither", server.ServerName)
+	}
+	fmt.Fprintf(os.Stderr, "SFTP is not available on %s; copying with scp\n", server.ServerName)
+	return nil
+}
+
+// sftpUpload mirrors scp semantics: an existing directory receives the source by name,
+// anything else is the destination itself.
+func sftpUpload(ctx context.Context, server models.Server, localPath, remotePath string, opts TransferOptions) error {
+	entries, err := localEntries(localPath)
+	if err != nil {
+		return err
+	}
+
+	client, closeFn, err := openSFTP(server)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	remote := homeRelativePath(remotePath)
+	destDir, name := path.Dir(remote), path.Base(remote)
+	if info, err := client.Stat(remote); remotePath == "" || strings.HasSuffix(remotePath, "/") || (err == nil && info.IsDir()) {
+		destDir, name = remote, filepath.Base(localPath)
+	}
+	if err := client.MkdirAll(destDir); err != nil {
+		return fmt.Errorf("failed to create remote directory: %w", err)
+	}
+
+	t := newSFTPTransfer(ctx, server, name, entries, opts)
+	for _, entry := range entries {
+		dest := path.Join(destDir, name, filepath.ToSlash(entry.rel))
+		if entry.isDir {
+			if err := client.MkdirAll(dest); err != nil {
+				return fmt.Errorf("failed to create %s: %w", dest, err)
+			}
+			continue
+		}
+		if err := t.upload(client, entry, filepath.Join(localPath, entry.rel), dest); err != nil {
+			return fmt.Errorf("upload of %s failed: %w", entry.rel, err)
+		}
+	}
+	return nil
+}
+
+// sftpDownload is the reverse of sftpUpload, using the same rules as the scp download.
+func sftpDownload(ctx context.Context, server models.Server, remotePath, localPath string, opts TransferOptions) error {
+	client, closeFn, err := openSFTP(server)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	remote := homeRelativePath(path.Clean(remotePath))
+	entries, err := remoteEntries(client, remote)
+	if err != nil {
+		return err
+	}
+
+	destDir, name := localDestination(localPath, path.Base(path.Clean(remotePath)))
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return err
+	}
+
+	t := newSFTPTransfer(ctx, server, name, entries, opts)
+	for _, entry := range entries {
+		dest := filepath.Join(destDir, name, filepath.FromSlash(entry.rel))
+		if entry.isDir {
+			if err := os.MkdirAll(dest, 0o755); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := t.download(client, entry, path.Join(remote, entry.rel), dest); err != nil {
+			return fmt.Errorf("download of %s failed: %w", entry.rel, err)
+		}
+	}
+	return nil
+}
+
+// localEntries lists a file, or a directory and everything below it. Anything but
+// regular files and directories is skipped.
+func localEntries(root string) ([]sftpEntry, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []sftpEntry{{rel: "", size: info.Size(), mode: info.Mode()}}, nil
+	}
+
+	var entries []sftpEntry
+	err = filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, p)
+		if rel == "." {
+			rel = ""
+		}
+		switch {
+		case info.IsDir():
+			entries = append(entries, sftpEntry{rel: rel, isDir: true, mode: info.Mode()})
+		case info.Mode().IsRegular():
+			entries = append(entries, sftpEntry{rel: rel, size: info.Size(), mode: info.Mode()})
+		}
+		return nil
+	})
+	return entries, err
+}
+
+// remoteEntries is localEntries for the server side.
+func remoteEntries(client *sftp.Client, root string) ([]sftpEntry, error) {
+	info, err := client.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []sftpEntry{{rel: "", size: info.Size(), mode: info.Mode()}}, nil
+	}
+
+	var entries []sftpEntry
+	walker := client.Walk(root)
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			return nil, err
+		}
+		rel := walker.Path()
+		switch {
+		case rel == root:
+			rel = ""
+		case root != ".":
+			rel = strings.TrimPrefix(strings.TrimPrefix(rel, root), "/")
+		}
+		info := walker.Stat()
+		switch {
+		case info.IsDir():
+			entries = append(entries, sftpEntry{rel: rel, isDir: true, mode: info.Mode()})
+		case info.Mode().IsRegular():
+			entries = append(entries, sftpEntry{rel: rel, size: info.Size(), mode: info.Mode()})
+		}
+	}
+	return entries, nil
+}
+
+// sftpTransfer tracks overall progress across the files of one Upload, Download or Copy.
+type sftpTransfer struct {
+	ctx       context.Context
+	server    models.Server
+	name      string
+	opts      TransferOptions
+	totalSize int64
+	totalDone int64
+}
+
+func newSFTPTransfer(ctx context.Context, server models.Server, name string, entries []sftpEntry, opts TransferOptions) *sftpTransfer {
+	t := &sftpTransfer{ctx: ctx, server: server, name: name, opts: opts}
+	for _, entry := range entries {
+		t.totalSize += entry.size
+	}
+	return t
+}
+
+// upload copies one file into dest+partSuffix, resuming from its size, then verifies and renames it.
+func (t *sftpTransfer) upload(client *sftp.Client, entry sftpEntry, localPath, dest string) error {
+	part := dest + partSuffix
+	var offset int64
+	if info, err := client.Stat(part); err == nil && info.Size() <= entry.size {
+		offset = info.Size()
+	}
+
+	for {
+		if err := t.uploadFrom(client, entry, localPath, part, offset); err != nil {
+			return err
+		}
+
+		verified, err := t.verify(entry, offset > 0, localPath, part, func() (int64, error) {
+			info, err := client.Stat(part)
+			if err != nil {
+				return 0, err
+			}
+			return info.Size(), nil
+		}, func() (string, error) {
+			return remoteChecksum(t.ctx, t.server, part)
+		})
+		if err != nil && offset > 0 {
+			// 이어받은 앞부분이 원본과 달랐으므로 처음부터 다시 전송
+			offset = 0
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := client.PosixRename(part, dest); err != nil {
+			// posix-rename 확장이 없는 서버는 기존 파일을 지운 뒤 이름 변경
+			client.Remove(dest)
+			if err := client.Rename(part, dest); err != nil {
+				return err
+			}
+		}
+		client.Chmod(dest, entry.mode.Perm())
+		t.finish(entry, offset, verified)
+		return nil
+	}
+}
+
+func (t *sftpTransfer) uploadFrom(client *sftp.Client, entry sftpEntry, localPath, part string, offset int64) error {
+	src, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	dst, err := client.OpenFile(part, flags)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	if _, err := src.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := dst.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, err = dst.ReadFromWithConcurrency(t.progressReader(src, entry, offset), 0)
+	if err != nil {
+		return err
+	}
+	return dst.Close()
+}
+
+// download copies one file into dest+partSuffix, resuming from its size, then verifies and renames it.
+func (t *sftpTransfer) download(client *sftp.Client, entry sftpEntry, remotePath, dest string) error {
+	part := dest + partSuffix
+	var offset int64
+	if info, err := os.Stat(part); err == nil && info.Size() <= entry.size {
+		offset = info.Size()
+	}
+
+	for {
+		if err := t.downloadFrom(client, entry, remotePath, part, offset); err != nil {
+			return err
+		}
+
+		verified, err := t.verify(entry, offset > 0, part, remotePath, func() (int64, error) {
+			info, err := os.Stat(part)
+			if err != nil {
+				return 0, err
+			}
+			return info.Size(), nil
+		}, func() (string, error) {
+			return remoteChecksum(t.ctx, t.server, remotePath)
+		})
+		if err != nil && offset > 0 {
+			// 이어받은 앞부분이 원본과 달랐으므로 처음부터 다시 전송
+			offset = 0
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := os.Rename(part, dest); err != nil {
+			return err
+		}
+		os.Chmod(dest, entry.mode.Perm())
+		t.finish(entry, offset, verified)
+		return nil
+	}
+}
+
+func (t *sftpTransfer) downloadFrom(client *sftp.Client, entry sftpEntry, remotePath, part string, offset int64) error {
+	src, err := client.Open(remotePath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	dst, err := os.OpenFile(part, flags, 0o644)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	if _, err := src.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := dst.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	if _, err := src.WriteTo(t.progressWriter(dst, entry, offset)); err != nil {
+		return err
+	}
+	return dst.Close()
+}
+
+// verify checks the transferred size, and the SHA-256 of both sides when --checksum is set
+// or the file was resumed. A resumed file that cannot be checksummed fails verification, so it
+// is transferred again from the start rather than trusted on its size.
+// localPath is the file on this machine; remotePath only names the other side in errors.
+func (t *sftpTransfer) verify(entry sftpEntry, resumed bool, localPath, remotePath string,
+	size func() (int64, error), remoteSum func() (string, error)) (string, error) {
+	got, err := size()
+	if err != nil {
+		return "", err
+	}
+	if got != entry.size {
+		return "", fmt.Errorf("size mismatch: expected %d bytes, got %d", entry.size, got)
+	}
+	if !t.opts.Checksum && !resumed {
+		return "size", nil
+	}
+
+	remote, err := remoteSum()
+	if err != nil {
+		return "", fmt.Errorf("failed to checksum %s: %w", remotePath, err)
+	}
+	local, err := localChecksum(localPath)
+	if err != nil {
+		return "", err
+	}
+	if local != remote {
+		return "", fmt.Errorf("checksum mismatch")
+	}
+	return "sha256", nil
+}
+
+func localChecksum(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// remoteChecksum hashes p on the server. Large files take a while, so it runs without the usual
+// command timeout and stops only when ctx is cancelled.
+func remoteChecksum(ctx context.Context, server models.Server, p string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	if err := streamCommand(ctx, server, "sha256sum "+remoteShellPath(p), nil, &stdout, &stderr); err != nil {
+		return "", fmt.Errorf("%w\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+	output := strings.TrimSpace(stdout.String())
+	sum, _, _ := strings.Cut(output, " ")
+	if len(sum) != sha256.Size*2 {
+		return "", fmt.Errorf("unexpected sha256sum output: %s", output)
+	}
+	return sum, nil
+}
+
+func (t *sftpTransfer) finish(entry sftpEntry, resumed int64, verified string) {
+	t.totalDone += entry.size
+	t.report(TransferProgress{Path: entry.rel, Size: entry.size, Done: entry.size, Resumed: resumed, Verified: verified}, 0)
+}
+
+// report fills in the totals; done is the progress of the current file not yet counted in totalDone.
+func (t *sftpTransfer) report(p TransferProgress, done int64) {
+	if t.opts.Progress == nil {
+		return
+	}
+	p.Path = path.Join(t.name, filepath.ToSlash(p.Path))
+	p.TotalSize = t.totalSize
+	p.TotalDone = t.totalDone + done
+	t.opts.Progress(p)
+}
+
+func (t *sftpTransfer) progressReader(r io.Reader, entry sftpEntry, offset int64) io.Reader {
+	return &progressCounter{reader: r, t: t, entry: entry, offset: offset, done: offset}
+}
+
+func (t *sftpTransfer) progressWriter(w io.Writer, entry sftpEntry, offset int64) io.Writer {
+	return &progressCounter{writer: w, t: t, entry: entry, offset: offset, done: offset}
+}
+
+// progressCounter counts bytes passing through a reader or writer and reports them at most every progressInterval.
+// It fails once the transfer's context is cancelled, leaving the part file for a later resume.
+type progressCounter struct {
+	reader io.Reader
+	writer io.Writer
+
+	t      *sftpTransfer
+	entry  sftpEntry
+	offset int64
+	done   int64
+	last   time.Time
+}
+
+func (c *progressCounter) Read(p []byte) (int, error) {
+	if err := c.t.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := c.reader.Read(p)
+	c.add(n)
+	return n, err
+}
+
+func (c *progressCounter) Write(p []byte) (int, error) {
+	if err := c.t.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := c.writer.Write(p)
+	c.add(n)
+	return n, err
+}
+
+func (c *progressCounter) add(n int) {
+	c.done += int64(n)
+	if time.Since(c.last) < progressInterval {
+		return
+	}
+	c.last = time.Now()
+	c.t.report(TransferProgress{Path: c.entry.rel, Size: c.entry.size, Done: c.done, Resumed: c.offset}, c.done)
+}
@@ -0,0 +1,23 @@
+package ssh
+
+import "testing"
+
+func TestSFTPSubsystemRefused(t *testing.T) {
+	tests := []struct {
+		stderr string
+		want   bool
+	}{
+		{"subsystem request failed on channel 0", true},
+		{"Request for subsystem 'sftp' failed on channel 0", true},
+		{"sh: 1: /usr/lib/openssh/sftp-server: not found", true},
+		{"ssh: Could not resolve hostname prdo: Name or service not known", false},
+		{"user@10.0.0.1: Permission denied (publickey).", false},
+		{"Host key verification failed.", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := sftpSubsystemRefused(tt.stderr); got != tt.want {
+			t.Errorf("sftpSubsystemRefused(%q) = %v, want %v", tt.stderr, got, tt.want)
+		}
+	}
+}
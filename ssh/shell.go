@@ -0,0 +1,34 @@
+package ssh
+
+import "strings"
+
+// shellQuote quotes s for a POSIX shell on the remote side.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// ShellCommand builds a remote command line from args, quoting each one so it arrives as a
+// single argument. A lone argument is left for the remote shell to parse, pipes and all.
+func ShellCommand(args []string) string {
+	if len(args) == 1 {
+		return args[0]
+	}
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// remoteShellPath quotes a remote path while keeping a leading ~ expandable, as scp does.
+func remoteShellPath(p string) string {
+	switch {
+	case p == "":
+		return "."
+	case p == "~":
+		return `"$HOME"`
+	case strings.HasPrefix(p, "~/"):
+		return `"$HOME"/` + shellQuote(p[2:])
+	}
+	return shellQuote(p)
+}
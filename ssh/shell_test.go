@@ -0,0 +1,20 @@
+package ssh
+
+import "testing"
+
+func TestShellCommand(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"uptime"}, "uptime"},
+		{[]string{"df -h / | tail -1"}, "df -h / | tail -1"},
+		{[]string{"grep", "a b", "f"}, "'grep' 'a b' 'f'"},
+		{[]string{"echo", "it's", "$HOME"}, `'echo' 'it'\''s' '$HOME'`},
+	}
+	for _, tt := range tests {
+		if got := ShellCommand(tt.args); got != tt.want {
+			t.Errorf("ShellCommand(%q) = %s, want %s", tt.args, got, tt.want)
+		}
+	}
+}
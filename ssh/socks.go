@@ -0,0 +1,118 @@
+package ssh
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+)
+
+// SOCKS5 protocol constants (RFC 1928) used by dynamic forwarding.
+const (
+	socksVersion   = 5
+	socksNoAuth    = 0
+	socksNoMethods = 0xff
+	socksConnect   = 1
+	socksIPv4      = 1
+	socksDomain    = 3
+	socksIPv6      = 4
+
+	socksSucceeded        = 0
+	socksHostUnreachable  = 4
+	socksCommandRejected  = 7
+	socksAddressRejected  = 8
+	socksReplyHeaderBytes = 10
+)
+
+// socksHandshake performs the server side of a SOCKS5 CONNECT on conn and dials the
+// requested address with dial. Only the no-authentication method is offered.
+func socksHandshake(conn net.Conn, dial func(network, addr string) (net.Conn, error)) (net.Conn, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, err
+	}
+	if header[0] != socksVersion {
+		return nil, fmt.Errorf("unsupported SOCKS version %d", header[0])
+	}
+
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return nil, err
+	}
+	method := byte(socksNoMethods)
+	for _, m := range methods {
+		if m == socksNoAuth {
+			method = socksNoAuth
+		}
+	}
+	if _, err := conn.Write([]byte{socksVersion, method}); err != nil {
+		return nil, err
+	}
+	if method == socksNoMethods {
+		return nil, fmt.Errorf("SOCKS client offered no supported auth method")
+	}
+
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return nil, err
+	}
+	if request[1] != socksConnect {
+		socksReply(conn, socksCommandRejected)
+		return nil, fmt.Errorf("unsupported SOCKS command %d", request[1])
+	}
+
+	var host string
+	switch request[3] {
+	case socksIPv4, socksIPv6:
+		size := net.IPv4len
+		if request[3] == socksIPv6 {
+			size = net.IPv6len
+		}
+		ip := make([]byte, size)
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return nil, err
+		}
+		host = net.IP(ip).String()
+	case socksDomain:
+		size := make([]byte, 1)
+		if _, err := io.ReadFull(conn, size); err != nil {
+			return nil, err
+		}
+		name := make([]byte, size[0])
+		if _, err := io.ReadFull(conn, name); err != nil {
+			return nil, err
+		}
+		host = string(name)
+	default:
+		socksReply(conn, socksAddressRejected)
+		return nil, fmt.Errorf("unsupported SOCKS address type %d", request[3])
+	}
+
+	portBytes := make([]byte, 2)
+	if _, err := io.ReadFull(conn, portBytes); err != nil {
+		return nil, err
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(portBytes))))
+
+	target, err := dial("tcp", addr)
+	if err != nil {
+		socksReply(conn, socksHostUnreachable)
+		return nil, fmt.Errorf("connect to %s: %w", addr, err)
+	}
+	if err := socksReply(conn, socksSucceeded); err != nil {
+		target.Close()
+		return nil, err
+	}
+	return target, nil
+}
+
+// socksReply sends a reply with an unspecified bound address; clients do not need it for CONNECT.
+func socksReply(conn net.Conn, status byte) error {
+	reply := make([]byte, socksReplyHeaderBytes)
+	reply[0] = socksVersion
+	reply[1] = status
+	reply[3] = socksIPv4
+	_, err := conn.Write(reply)
+	return err
+}
@@ -0,0 +1,148 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"remotelink/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// statsFormat prints one JSON object per container. The fields are listed explicitly because
+// `--format json` and `{{json .}}` name them differently in docker, podman and nerdctl.
+const statsFormat = `{"name":{{json .Name}},"cpu":{{json .CPUPerc}},"mem":{{json .MemUsage}},"mem_perc":{{json .MemPerc}},` +
+	`"net":{{json .NetIO}},"block":{{json .BlockIO}},"pids":{{json .PIDs}}}`
+
+// statsFrameEnd is the line StreamStats prints after every sample.
+const statsFrameEnd = "--"
+
+// ContainerStats is one sample of a container's resource usage. It is also the --json output.
+type ContainerStats struct {
+	Name       string  `json:"name"`
+	CPUPercent float64 `json:"cpu_percent"`
+	MemUsage   uint64  `json:"mem_usage_bytes"`
+	MemLimit   uint64  `json:"mem_limit_bytes"`
+	MemPercent float64 `json:"mem_percent"`
+	NetRx      uint64  `json:"net_rx_bytes"`
+	NetTx      uint64  `json:"net_tx_bytes"`
+	BlockRead  uint64  `json:"block_read_bytes"`
+	BlockWrite uint64  `json:"block_write_bytes"`
+	PIDs       int     `json:"pids"`
+}
+
+// FetchStats takes a single sample of the named containers, or of every running container when
+// none are named.
+func FetchStats(server models.Server, containers []string) ([]ContainerStats, error) {
+	output, err := ExecuteRemoteCommand(server, runtimeCommand(server, statsCommand(containers)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read container stats: %w", err)
+	}
+
+	var stats []ContainerStats
+	for _, line := range strings.Split(output, "\n") {
+		if sample, ok := parseStatsLine(line); ok {
+			stats = append(stats, sample)
+		}
+	}
+	return stats, nil
+}
+
+// StreamStats samples the containers every interval, passing each complete sample to onSample,
+// until the runtime fails or ctx is cancelled.
+func StreamStats(ctx context.Context, server models.Server, containers []string, interval time.Duration, onSample func([]ContainerStats)) error {
+	// --no-stream 반복은 런타임마다 다른 스트리밍 출력(화면 지우기 등)을 피하고 샘플 경계를 분명히 함
+	command := runtimeCommand(server, fmt.Sprintf("while :; do %s || exit; echo %s; sleep %s; done",
+		statsCommand(containers), statsFrameEnd, strconv.FormatFloat(interval.Seconds(), 'f', -1, 64)))
+
+	var stderr bytes.Buffer
+	err := streamCommand(ctx, server, command, nil, &statsWriter{onSample: onSample}, &stderr)
+	if err != nil && ctx.Err() == nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("failed to read container stats: %w\n%s", err, msg)
+		}
+		return fmt.Errorf("failed to read container stats: %w", err)
+	}
+	return err
+}
+
+func statsCommand(containers []string) string {
+	args := []string{`"$rt"`, "stats", "--no-stream", "--format", shellQuote(statsFormat)}
+	for _, container := range containers {
+		args = append(args, shellQuote(container))
+	}
+	return strings.Join(args, " ")
+}
+
+// parseStatsLine parses one line of statsFormat output. ok is false for anything else.
+func parseStatsLine(line string) (stats ContainerStats, ok bool) {
+	var raw struct {
+		Name    string `json:"name"`
+		CPU     string `json:"cpu"`
+		Mem     string `json:"mem"`
+		MemPerc string `json:"mem_perc"`
+		Net     string `json:"net"`
+		Block   string `json:"block"`
+		// PIDs는 런타임에 따라 문자열 또는 숫자
+		PIDs any `json:"pids"`
+	}
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &raw) != nil || raw.Name == "" {
+		return ContainerStats{}, false
+	}
+
+	stats = ContainerStats{
+		Name:       strings.TrimPrefix(raw.Name, "/"),
+		CPUPercent: parsePercent(raw.CPU),
+		MemPercent: parsePercent(raw.MemPerc),
+	}
+	stats.MemUsage, stats.MemLimit = parseBytePair(raw.Mem)
+	stats.NetRx, stats.NetTx = parseBytePair(raw.Net)
+	stats.BlockRead, stats.BlockWrite = parseBytePair(raw.Block)
+	stats.PIDs, _ = strconv.Atoi(fmt.Sprint(raw.PIDs))
+	return stats, true
+}
+
+// parsePercent parses "12.34%"; unavailable values such as "--" are 0.
+func parsePercent(s string) float64 {
+	percent, _ := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	return percent
+}
+
+// parseBytePair parses "1.5MiB / 7.6GiB" as printed for memory, network and block I/O.
+func parseBytePair(s string) (first, second uint64) {
+	a, b, _ := strings.Cut(s, "/")
+	first, _ = humanize.ParseBytes(strings.TrimSpace(a))
+	second, _ = humanize.ParseBytes(strings.TrimSpace(b))
+	return first, second
+}
+
+// statsWriter collects StreamStats output into samples, handing each one over at statsFrameEnd.
+type statsWriter struct {
+	onSample func([]ContainerStats)
+	buf      []byte
+	sample   []ContainerStats
+}
+
+func (w *statsWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := strings.TrimSpace(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+
+		if line == statsFrameEnd {
+			w.onSample(w.sample)
+			w.sample = nil
+		} else if stats, ok := parseStatsLine(line); ok {
+			w.sample = append(w.sample, stats)
+		}
+	}
+}
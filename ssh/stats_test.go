@@ -0,0 +1,73 @@
+package ssh
+
+import "testing"
+
+func TestParseStatsLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		want   ContainerStats
+		wantOK bool
+	}{
+		{
+			name: "docker",
+			line: `{"name":"web","cpu":"12.50%","mem":"100MiB / 2GiB","mem_perc":"4.88%","net":"1.2kB / 3.4MB","block":"0B / 8kB","pids":"7"}`,
+			want: ContainerStats{Name: "web", CPUPercent: 12.5, MemUsage: 100 << 20, MemLimit: 2 << 30, MemPercent: 4.88,
+				NetRx: 1200, NetTx: 3400000, BlockWrite: 8000, PIDs: 7},
+			wantOK: true,
+		},
+		{
+			name:   "numeric pids and leading slash",
+			line:   `  {"name":"/db","cpu":"0.00%","mem":"1GiB / 4GiB","mem_perc":"25.00%","net":"0B / 0B","block":"0B / 0B","pids":3}`,
+			want:   ContainerStats{Name: "db", MemUsage: 1 << 30, MemLimit: 4 << 30, MemPercent: 25, PIDs: 3},
+			wantOK: true,
+		},
+		{
+			name:   "unavailable values",
+			line:   `{"name":"stopped","cpu":"--","mem":"-- / --","mem_perc":"--","net":"--","block":"--","pids":"--"}`,
+			want:   ContainerStats{Name: "stopped"},
+			wantOK: true,
+		},
+		{name: "frame end", line: statsFrameEnd},
+		{name: "empty", line: ""},
+		{name: "warning", line: "WARNING: cgroup v1 not supported"},
+		{name: "bad json", line: `{"name":`},
+		{name: "no name", line: `{"cpu":"1%"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseStatsLine(tt.line)
+			if ok != tt.wantOK {
+				t.Fatalf("parseStatsLine() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("parseStatsLine() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBytePair(t *testing.T) {
+	tests := []struct {
+		s          string
+		wantFirst  uint64
+		wantSecond uint64
+	}{
+		{"1.5MiB / 2GiB", 1572864, 2 << 30},
+		{"648B / 1.09kB", 648, 1090},
+		{"10MB/20MB", 10000000, 20000000},
+		{"512KiB", 512 << 10, 0},
+		{"-- / --", 0, 0},
+		{"", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			first, second := parseBytePair(tt.s)
+			if first != tt.wantFirst || second != tt.wantSecond {
+				t.Errorf("parseBytePair(%q) = %d, %d, want %d, %d", tt.s, first, second, tt.wantFirst, tt.wantSecond)
+			}
+		})
+	}
+}
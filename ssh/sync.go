@@ -0,0 +1,93 @@
+package ssh
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"remotelink/models"
+	"slices"
+	"strings"
+)
+
+// PushFiles mirrors changes below localRoot into remoteDir on the server, or inside container when
+// it is not empty: the changed paths are sent as one tar stream and the removed paths deleted.
+// Paths are slash-separated and relative to both roots; directories are sent with their contents
+// minus anything ignore matches.
+func PushFiles(server models.Server, container, localRoot, remoteDir string, changed, removed []string, ignore *IgnoreRules) error {
+	for _, rel := range slices.Concat(changed, removed) {
+		// 잘못된 경로로 대상 디렉터리 전체나 바깥을 지우지 않도록 확인
+		if rel == "" || path.IsAbs(rel) || path.Clean(rel) != rel || rel == "." || rel == ".." || strings.HasPrefix(rel, "../") {
+			return fmt.Errorf("invalid sync path %q", rel)
+		}
+	}
+
+	if container != "" {
+		return pushToContainer(server, container, localRoot, remoteDir, changed, removed, ignore)
+	}
+
+	dir := remoteShellPath(remoteDir)
+	commands := []string{"mkdir -p " + dir, "cd " + dir}
+	if len(removed) > 0 {
+		commands = append(commands, "rm -rf -- "+quotePaths(removed, ""))
+	}
+	if len(changed) > 0 {
+		commands = append(commands, "tar -xf -")
+	}
+	command := strings.Join(commands, " && ")
+
+	if len(changed) == 0 {
+		if err := BackendFor(server).Stream(server, command, nil, nil, nil); err != nil {
+			return fmt.Errorf("sync failed: %w", err)
+		}
+		return nil
+	}
+	return streamTar(server, command, localRoot, changed, ignore)
+}
+
+// pushToContainer uses the runtime's exec and `cp -` so the container needs no shell, and no
+// tar except with nerdctl and in pods.
+func pushToContainer(server models.Server, container, localRoot, remoteDir string, changed, removed []string, ignore *IgnoreRules) error {
+	if remoteDir == "" {
+		return fmt.Errorf("a destination path inside the container is required")
+	}
+
+	t := targetFor(server, container)
+	commands := []string{t.exec(false, false) + " mkdir -p " + shellQuote(remoteDir)}
+	if len(removed) > 0 {
+		commands = append(commands, t.exec(false, false)+" rm -rf -- "+quotePaths(removed, remoteDir))
+	}
+	if len(changed) > 0 {
+		commands = append(commands, t.copyIn(remoteDir))
+	}
+	command := t.command(commands...)
+
+	if len(changed) == 0 {
+		if err := BackendFor(server).Stream(server, command, nil, nil, nil); err != nil {
+			return fmt.Errorf("sync failed: %w", err)
+		}
+		return nil
+	}
+	return streamTar(server, command, localRoot, changed, ignore)
+}
+
+func streamTar(server models.Server, command, localRoot string, changed []string, ignore *IgnoreRules) error {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeTarFiles(pw, localRoot, changed, ignore.Match))
+	}()
+
+	err := BackendFor(server).Stream(server, command, pr, nil, nil)
+	pr.Close()
+	if err != nil {
+		return fmt.Errorf("sync failed: %w", err)
+	}
+	return nil
+}
+
+func quotePaths(rels []string, dir string) string {
+	quoted := make([]string, len(rels))
+	for i, rel := range rels {
+		quoted[i] = shellQuote(path.Join(dir, rel))
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,37 @@
+//go:build windows
+
+package ssh
+
+import (
+	"time"
+
+	"golang.org/x/term"
+)
+
+// watchWindowSize polls the console size, since Windows has no SIGWINCH.
+func watchWindowSize(fd int, onResize func(width, height int)) (stop func()) {
+	ticker := time.NewTicker(250 * time.Millisecond)
+	done := make(chan struct{})
+	lastWidth, lastHeight, _ := term.GetSize(fd)
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				width, height, err := term.GetSize(fd)
+				if err != nil || (width == lastWidth && height == lastHeight) {
+					continue
+				}
+				lastWidth, lastHeight = width, height
+				onResize(width, height)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
@@ -1,66 +1,167 @@
 package ssh
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
+	"path"
+	"path/filepath"
 	"remotelink/models"
-	"runtime"
+	"strings"
 )
 
-// nullDevice returns the null device path for the current OS.
-func nullDevice() string {
-	if runtime.GOOS == "windows" {
-		return "NUL"
+var errContainerOptions = errors.New("container transfers use the runtime's cp; drop --delete, --exclude, --checksum and --dry-run")
+
+// Upload transfers a local file or directory to a remote server,
+// or into a container on that server when container is not empty.
+// rsync falls back to SFTP when it is missing on either side, and SFTP to scp
+// when the server has no sftp subsystem. Cancelling ctx stops rsync and SFTP transfers;
+// a partial SFTP file is resumed by the next transfer.
+func Upload(ctx context.Context, server models.Server, container, localPath, remotePath string, opts TransferOptions) error {
+	method, err := transferMethod(server, opts)
+	if err != nil {
+		return err
+	}
+	if container != "" {
+		if opts.needsRsync() || opts.Checksum {
+			return errContainerOptions
+		}
+		return uploadToContainer(server, container, localPath, remotePath)
 	}
-	return "/dev/null"
-}
 
-// buildSCPArgs builds common SCP arguments for the given server.
-func buildSCPArgs(server models.Server) []string {
-	args := []string{
-		"-F", nullDevice(),
-		"-P", fmt.Sprintf("%d", server.Port),
-		"-o", "StrictHostKeyChecking=no",
-		"-o", "BatchMode=yes",
-		"-r",
+	if method == TransferRsync {
+		done, err := rsyncUpload(ctx, server, localPath, remotePath, opts)
+		if done || err != nil {
+			return err
+		}
+		if err := rsyncFallback(server, opts); err != nil {
+			return err
+		}
+		method = TransferSFTP
 	}
+	if method == TransferSFTP {
+		err := sftpUpload(ctx, server, localPath, remotePath, opts)
+		if !errors.Is(err, errSFTPUnavailable) {
+			return err
+		}
+		if err := sftpFallback(server, opts); err != nil {
+			return err
+		}
+	}
+	return BackendFor(server).Upload(server, localPath, remotePath)
+}
 
-	if server.KeyPath != "" {
-		args = append(args, "-i", server.KeyPath)
+// Download transfers a remote file or directory to the local machine,
+// reading from a container on the server when container is not empty.
+// It falls back like Upload.
+func Download(ctx context.Context, server models.Server, container, remotePath, localPath string, opts TransferOptions) error {
+	method, err := transferMethod(server, opts)
+	if err != nil {
+		return err
+	}
+	if container != "" {
+		if opts.needsRsync() || opts.Checksum {
+			return errContainerOptions
+		}
+		return downloadFromContainer(server, container, remotePath, localPath)
 	}
 
-	return args
+	if method == TransferRsync {
+		done, err := rsyncDownload(ctx, server, remotePath, localPath, opts)
+		if done || err != nil {
+			return err
+		}
+		if err := rsyncFallback(server, opts); err != nil {
+			return err
+		}
+		method = TransferSFTP
+	}
+	if method == TransferSFTP {
+		err := sftpDownload(ctx, server, remotePath, localPath, opts)
+		if !errors.Is(err, errSFTPUnavailable) {
+			return err
+		}
+		if err := sftpFallback(server, opts); err != nil {
+			return err
+		}
+	}
+	return BackendFor(server).Download(server, remotePath, localPath)
 }
 
-// Upload transfers a local file or directory to a remote server using scp.
-func Upload(server models.Server, localPath, remotePath string) error {
-	args := buildSCPArgs(server)
-	args = append(args, localPath, fmt.Sprintf("%s@%s:%s", server.Username, server.HostIp, remotePath))
+// uploadToContainer streams a tar archive into the runtime's `cp -` so nothing is staged on the host.
+func uploadToContainer(server models.Server, container, localPath, remotePath string) error {
+	if _, err := os.Stat(localPath); err != nil {
+		return err
+	}
+
+	destDir, name, err := containerDestination(server, container, remotePath, filepath.Base(localPath))
+	if err != nil {
+		return err
+	}
 
-	cmd := exec.Command("scp", args...)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeTar(pw, localPath, name))
+	}()
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("scp upload failed: %w", err)
+	t := targetFor(server, container)
+	command := t.command(t.copyIn(destDir))
+	err = BackendFor(server).Stream(server, command, pr, nil, nil)
+	pr.Close()
+	if err != nil {
+		return fmt.Errorf("container upload failed: %w", err)
 	}
 	return nil
 }
 
-// Download transfers a remote file or directory to the local machine using scp.
-func Download(server models.Server, remotePath, localPath string) error {
-	args := buildSCPArgs(server)
-	args = append(args, fmt.Sprintf("%s@%s:%s", server.Username, server.HostIp, remotePath), localPath)
+// downloadFromContainer reads the tar archive produced by the runtime's `cp <container>:<path> -`.
+func downloadFromContainer(server models.Server, container, remotePath, localPath string) error {
+	cleaned := path.Clean(remotePath)
+	destDir, name := localDestination(localPath, path.Base(cleaned))
+
+	pr, pw := io.Pipe()
+	extracted := make(chan error, 1)
+	go func() {
+		err := extractTar(pr, destDir, name)
+		// 추출 실패 시 원격 출력 쓰기가 막히지 않도록 파이프 종료
+		pr.CloseWithError(err)
+		extracted <- err
+	}()
 
-	cmd := exec.Command("scp", args...)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	t := targetFor(server, container)
+	command := t.command(t.copyOut(cleaned))
+	err := BackendFor(server).Stream(server, command, nil, pw, nil)
+	pw.CloseWithError(err)
+	extractErr := <-extracted
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("scp download failed: %w", err)
+	if err != nil {
+		return fmt.Errorf("container download failed: %w", err)
+	}
+	if extractErr != nil {
+		return fmt.Errorf("container download failed: %w", extractErr)
 	}
 	return nil
 }
+
+// containerDestination applies the same scp semantics as remoteDestination, inside a container.
+func containerDestination(server models.Server, container, remotePath, srcName string) (dir, name string, err error) {
+	if remotePath == "" {
+		return "", "", fmt.Errorf("a destination path inside the container is required")
+	}
+	if strings.HasSuffix(remotePath, "/") {
+		return remotePath, srcName, nil
+	}
+
+	t := targetFor(server, container)
+	output, err := ExecuteRemoteCommand(server,
+		t.command(fmt.Sprintf("if %s test -d %s; then echo dir; fi", t.exec(false, false), shellQuote(remotePath))))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to inspect container path: %w", err)
+	}
+	if output == "dir" {
+		return remotePath, srcName, nil
+	}
+	return path.Dir(remotePath), path.Base(remotePath), nil
+}
@@ -0,0 +1,130 @@
+package tunnel
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"remotelink/models"
+	"time"
+)
+
+// daemonStartTimeout bounds how long Up waits for a freshly started daemon to listen.
+const daemonStartTimeout = 5 * time.Second
+
+// ErrDaemonNotRunning is returned when there is no daemon to talk to.
+var ErrDaemonNotRunning = errors.New("tunnel daemon is not running")
+
+// Up asks the daemon to keep forwards alive on the named server, starting the daemon if needed.
+// backend is used when the server does not set ssh_backend.
+func Up(name, server, backend string, forwards []models.Forward) (Status, error) {
+	if err := ensureDaemon(); err != nil {
+		return Status{}, err
+	}
+
+	resp, err := call(request{Op: opUp, Name: name, Server: server, Backend: backend, Forwards: forwards})
+	if err != nil {
+		return Status{}, err
+	}
+	return resp.Tunnels[0], nil
+}
+
+// Down stops the named tunnel, or every tunnel with all.
+func Down(name string, all bool) ([]Status, error) {
+	resp, err := call(request{Op: opDown, Name: name, All: all})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Tunnels, nil
+}
+
+// List returns the status of every tunnel. No daemon means no tunnels.
+func List() ([]Status, error) {
+	resp, err := call(request{Op: opList})
+	if errors.Is(err, ErrDaemonNotRunning) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp.Tunnels, nil
+}
+
+// Get returns the status of the named tunnel.
+func Get(name string) (Status, bool, error) {
+	statuses, err := List()
+	if err != nil {
+		return Status{}, false, err
+	}
+	for _, status := range statuses {
+		if status.Name == name {
+			return status, true, nil
+		}
+	}
+	return Status{}, false, nil
+}
+
+func call(req request) (response, error) {
+	conn, err := net.Dial("unix", SocketPath())
+	if err != nil {
+		return response{}, ErrDaemonNotRunning
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return response{}, err
+	}
+
+	var resp response
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return response{}, fmt.Errorf("no response from tunnel daemon: %w", err)
+	}
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return response{}, err
+	}
+	if resp.Error != "" {
+		return response{}, errors.New(resp.Error)
+	}
+	return resp, nil
+}
+
+// ensureDaemon starts "remotelink tunnel daemon" in the background unless one is already listening.
+func ensureDaemon() error {
+	if conn, err := net.Dial("unix", SocketPath()); err == nil {
+		conn.Close()
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	logFile, err := os.OpenFile(LogPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(exe, "tunnel", "daemon")
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = detachAttr()
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start tunnel daemon: %w", err)
+	}
+	cmd.Process.Release()
+
+	deadline := time.Now().Add(daemonStartTimeout)
+	for time.Now().Before(deadline) {
+		if conn, err := net.Dial("unix", SocketPath()); err == nil {
+			conn.Close()
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("tunnel daemon did not start; see %s", LogPath())
+}
@@ -0,0 +1,334 @@
+package tunnel
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path"
+	"remotelink/config"
+	"remotelink/models"
+	remotessh "remotelink/ssh"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Reconnect backoff: doubles from minBackoff up to maxBackoff, and starts over once a
+// connection has stayed up for stableAfter.
+const (
+	minBackoff  = time.Second
+	maxBackoff  = time.Minute
+	stableAfter = 30 * time.Second
+)
+
+// ErrDaemonRunning is returned by RunDaemon when another daemon already owns the socket.
+var ErrDaemonRunning = errors.New("tunnel daemon is already running")
+
+type daemon struct {
+	mu      sync.Mutex
+	tunnels map[string]*tunnel
+	servers []models.Server
+
+	listener net.Listener
+}
+
+// tunnel is one set of forwards kept alive on a server.
+type tunnel struct {
+	name     string
+	server   models.Server
+	forwards []models.Forward
+	stats    remotessh.ForwardStats
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	mu          sync.Mutex
+	state       string
+	lastError   string
+	reconnects  int
+	createdAt   time.Time
+	connectedAt time.Time
+}
+
+// RunDaemon serves tunnel requests on SocketPath until the last tunnel is brought down.
+// It expects config.LoadServers to have run; servers are re-read from disk on every "up".
+func RunDaemon() error {
+	socketPath := SocketPath()
+	if conn, err := net.Dial("unix", socketPath); err == nil {
+		conn.Close()
+		return ErrDaemonRunning
+	}
+	os.Remove(socketPath)
+
+	// 리슨하기 전에 소켓 디렉터리를 본인만 접근 가능하게 만듦 (기존 디렉터리의 권한도 바로잡음)
+	socketDir := path.Dir(socketPath)
+	if err := os.MkdirAll(socketDir, 0700); err != nil {
+		return err
+	}
+	if err := os.Chmod(socketDir, 0700); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", socketPath, err)
+	}
+	defer os.Remove(socketPath)
+	if err := os.Chmod(socketPath, 0600); err != nil {
+		listener.Close()
+		return err
+	}
+
+	d := &daemon{
+		tunnels:  map[string]*tunnel{},
+		servers:  config.Servers,
+		listener: listener,
+	}
+	// 설정을 다시 읽어도 연결 중인 터널과 경합하지 않도록 데몬이 가진 서버 목록으로 해석
+	remotessh.ResolveJumps = d.jumpChain
+
+	log.Printf("tunnel daemon listening on %s", socketPath)
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				log.Printf("no tunnels left, exiting")
+				return nil
+			}
+			return err
+		}
+		go d.serve(conn)
+	}
+}
+
+func (d *daemon) jumpChain(server models.Server) ([]models.Server, error) {
+	d.mu.Lock()
+	servers := d.servers
+	d.mu.Unlock()
+	return config.JumpChainIn(servers, server)
+}
+
+// serve answers one request on conn.
+func (d *daemon) serve(conn net.Conn) {
+	defer conn.Close()
+
+	var req request
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err == nil {
+		err = json.Unmarshal(line, &req)
+	}
+
+	var resp response
+	if err != nil {
+		resp.Error = "invalid request: " + err.Error()
+	} else {
+		resp = d.handle(req)
+	}
+	json.NewEncoder(conn).Encode(resp)
+}
+
+func (d *daemon) handle(req request) response {
+	switch req.Op {
+	case opUp:
+		status, err := d.up(req)
+		if err != nil {
+			return response{Error: err.Error()}
+		}
+		return response{Tunnels: []Status{status}}
+	case opDown:
+		stopped, err := d.down(req)
+		if err != nil {
+			return response{Error: err.Error()}
+		}
+		return response{Tunnels: stopped}
+	case opList:
+		return response{Tunnels: d.list()}
+	}
+	return response{Error: fmt.Sprintf("unknown op %q", req.Op)}
+}
+
+func (d *daemon) up(req request) (Status, error) {
+	servers, err := config.ReadServers()
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to read servers: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, exists := d.tunnels[req.Name]; exists {
+		return Status{}, fmt.Errorf("tunnel '%s' is already up", req.Name)
+	}
+
+	d.servers = servers
+	server, found := findServer(servers, req.Server)
+	if !found {
+		return Status{}, fmt.Errorf("server '%s' not found", req.Server)
+	}
+	if server.SSHBackend == "" {
+		server.SSHBackend = req.Backend
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &tunnel{
+		name:      req.Name,
+		server:    server,
+		forwards:  req.Forwards,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+		state:     StateConnecting,
+		createdAt: time.Now(),
+	}
+	d.tunnels[t.name] = t
+
+	log.Printf("[%s] up on %s", t.name, server.ServerName)
+	go t.run(ctx)
+	return t.status(), nil
+}
+
+func (d *daemon) down(req request) ([]Status, error) {
+	d.mu.Lock()
+	var stopping []*tunnel
+	if req.All {
+		for _, t := range d.tunnels {
+			stopping = append(stopping, t)
+		}
+	} else if t, ok := d.tunnels[req.Name]; ok {
+		stopping = append(stopping, t)
+	} else {
+		d.mu.Unlock()
+		return nil, fmt.Errorf("tunnel '%s' not found", req.Name)
+	}
+	for _, t := range stopping {
+		delete(d.tunnels, t.name)
+	}
+	idle := len(d.tunnels) == 0
+	d.mu.Unlock()
+
+	stopped := make([]Status, len(stopping))
+	for i, t := range stopping {
+		t.cancel()
+		<-t.done
+		stopped[i] = t.status()
+		log.Printf("[%s] down", t.name)
+	}
+
+	// 남은 터널이 없으면 응답을 보낸 뒤 데몬 종료
+	if idle {
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			if len(d.tunnels) == 0 {
+				d.listener.Close()
+			}
+		}()
+	}
+	return stopped, nil
+}
+
+func (d *daemon) list() []Status {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	statuses := make([]Status, 0, len(d.tunnels))
+	for _, t := range d.tunnels {
+		statuses = append(statuses, t.status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// run keeps the tunnel's forwards up until ctx is cancelled, reconnecting with backoff.
+func (t *tunnel) run(ctx context.Context) {
+	defer close(t.done)
+	backoff := minBackoff
+
+	for {
+		var connectedAt time.Time
+		forwards, err := remotessh.ResolveForwards(t.server, t.forwards)
+		if err == nil {
+			err = remotessh.Forward(ctx, t.server, forwards, remotessh.ForwardOptions{
+				Stats: &t.stats,
+				Ready: func() {
+					connectedAt = time.Now()
+					t.setConnected(connectedAt)
+					log.Printf("[%s] connected", t.name)
+				},
+			})
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = errors.New("forwarding ended")
+		}
+
+		if !connectedAt.IsZero() && time.Since(connectedAt) >= stableAfter {
+			backoff = minBackoff
+		}
+		t.setFailed(err)
+		log.Printf("[%s] %v; retrying in %s", t.name, err, backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+func (t *tunnel) setConnected(at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state = StateUp
+	t.lastError = ""
+	t.connectedAt = at
+}
+
+func (t *tunnel) setFailed(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state = StateReconnecting
+	t.lastError = err.Error()
+	t.connectedAt = time.Time{}
+	t.reconnects++
+}
+
+func (t *tunnel) status() Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	backend := t.server.SSHBackend
+	if backend == "" {
+		backend = remotessh.DefaultBackend
+	}
+	return Status{
+		Name:        t.name,
+		Server:      t.server.ServerName,
+		Backend:     backend,
+		Forwards:    t.forwards,
+		State:       t.state,
+		LastError:   t.lastError,
+		Sent:        t.stats.Sent.Load(),
+		Received:    t.stats.Received.Load(),
+		Connections: t.stats.Connections.Load(),
+		Reconnects:  t.reconnects,
+		CreatedAt:   t.createdAt,
+		ConnectedAt: t.connectedAt,
+	}
+}
+
+func findServer(servers []models.Server, name string) (models.Server, bool) {
+	for _, server := range servers {
+		if server.ServerName == name {
+			return server, true
+		}
+	}
+	return models.Server{}, false
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package tunnel
+
+import "syscall"
+
+// detachAttr puts the daemon in its own session so it outlives the terminal that started it.
+func detachAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
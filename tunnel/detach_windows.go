@@ -0,0 +1,15 @@
+//go:build windows
+
+package tunnel
+
+import "syscall"
+
+const (
+	detachedProcess       = 0x00000008
+	createNewProcessGroup = 0x00000200
+)
+
+// detachAttr starts the daemon without a console so it outlives the terminal that started it.
+func detachAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: detachedProcess | createNewProcessGroup}
+}
@@ -0,0 +1,81 @@
+// Package tunnel keeps port forwards alive in a background daemon and talks to it over a
+// Unix socket under ~/.remotelink.
+package tunnel
+
+import (
+	"path"
+	"remotelink/models"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// Tunnel states reported by the daemon.
+const (
+	StateConnecting   = "connecting"
+	StateUp           = "up"
+	StateReconnecting = "reconnecting"
+)
+
+// Request operations understood by the daemon.
+const (
+	opUp   = "up"
+	opDown = "down"
+	opList = "ls"
+)
+
+// request is one JSON line sent to the daemon.
+type request struct {
+	Op       string           `json:"op"`
+	Name     string           `json:"name,omitempty"`
+	Server   string           `json:"server,omitempty"`
+	Backend  string           `json:"backend,omitempty"`
+	Forwards []models.Forward `json:"forwards,omitempty"`
+	All      bool             `json:"all,omitempty"`
+}
+
+// response is the daemon's one JSON line reply.
+type response struct {
+	Error   string   `json:"error,omitempty"`
+	Tunnels []Status `json:"tunnels,omitempty"`
+}
+
+// Status is a snapshot of one tunnel.
+type Status struct {
+	Name        string           `json:"name"`
+	Server      string           `json:"server"`
+	Backend     string           `json:"backend"`
+	Forwards    []models.Forward `json:"forwards"`
+	State       string           `json:"state"`
+	LastError   string           `json:"last_error,omitempty"`
+	Sent        int64            `json:"sent"`
+	Received    int64            `json:"received"`
+	Connections int64            `json:"connections"`
+	Reconnects  int              `json:"reconnects"`
+	CreatedAt   time.Time        `json:"created_at"`
+	ConnectedAt time.Time        `json:"connected_at,omitzero"`
+}
+
+// Uptime is how long the current connection has been up, or zero when it is not.
+func (s Status) Uptime() time.Duration {
+	if s.State != StateUp || s.ConnectedAt.IsZero() {
+		return 0
+	}
+	return time.Since(s.ConnectedAt)
+}
+
+// SocketPath is where the daemon listens. It sits in a 0700 directory so the socket is never
+// reachable by other users, even before the daemon tightens its mode.
+func SocketPath() string {
+	return path.Join(stateDir(), "run", "tunnel.sock")
+}
+
+// LogPath is where the daemon's output goes.
+func LogPath() string {
+	return path.Join(stateDir(), "tunnel.log")
+}
+
+func stateDir() string {
+	home, _ := homedir.Dir()
+	return path.Join(home, ".remotelink")
+}